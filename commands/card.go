@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/card"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/profile"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+// speciesEntry returns the Dex entry of a caught Pokemon's species,
+// fetching and saving its species data if it was caught before that was
+// recorded.
+func speciesEntry(cfg *Config, p pokedex.Pokemon) (pokedex.DexEntry, error) {
+	dex, err := cfg.Pokedex.Dex()
+	if err != nil {
+		return pokedex.DexEntry{}, err
+	}
+
+	entry := pokedex.DexEntry{ID: p.SpeciesID, Name: p.Species}
+	for _, d := range dex {
+		if d.Name == p.Species {
+			entry = d
+		}
+	}
+	if entry.Info != nil {
+		return entry, nil
+	}
+
+	_, raw, err := pokeapi.GetPokemon(p.Species)
+	if err != nil {
+		return entry, fmt.Errorf("failed to fetch species data for %s: %w", p.Species, err)
+	}
+	info, err := pokedex.ParseSpeciesInfo(raw)
+	if err != nil {
+		return entry, err
+	}
+	if err := cfg.Pokedex.SetInfo(map[string]pokedex.SpeciesInfo{p.Species: info}); err != nil {
+		return entry, fmt.Errorf("could not update pokedex: %w", err)
+	}
+	entry.Info = &info
+	return entry, nil
+}
+
+// renderCard renders the card of a caught Pokemon's species from its
+// stored data, reusing a cached render when card caching is on.
+func renderCard(cfg *Config, p pokedex.Pokemon) ([]string, error) {
+	var settings profile.Settings
+	if cfg.Profile.Dir != "" {
+		var err error
+		if settings, err = cfg.Profile.Settings(); err != nil {
+			return nil, err
+		}
+	}
+	useCache := settings.CardCache == "on"
+
+	sprite, ok := card.SpriteByName(settings.Sprite, os.Getenv)
+	if !ok {
+		sprite = card.DetectSprite(os.Getenv)
+	}
+	renderer := card.NewRenderer(sprite)
+	if scaler, ok := card.ScalerByName(settings.Scaling); ok {
+		renderer.Scale = scaler
+	}
+	renderer.Width = terminal.Width()
+	ranking, err := pokedex.LoadStatTable()
+	if err != nil {
+		return nil, err
+	}
+	renderer.Ranking = ranking
+	cachePath := pokedex.CardPath(p.Species)
+	if useCache {
+		if lines, ok := card.ReadCached(cachePath, renderer.Key()); ok {
+			return lines, nil
+		}
+	}
+
+	entry, err := speciesEntry(cfg, p)
+	if err != nil {
+		return nil, err
+	}
+	img, err := card.LoadSprite(entry.Info.Sprite, pokedex.SpritePath(p.Species))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the sprite of %s: %w", p.Species, err)
+	}
+
+	lines := renderer.Render(card.Card{ID: entry.ID, Name: entry.Name, Info: *entry.Info, Sprite: img})
+	if useCache {
+		if err := card.WriteCached(cachePath, renderer.Key(), lines); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
@@ -1,31 +1,60 @@
 package commands
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokecache"
 	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/profile"
 )
 
 type Config struct {
 	Next     *string
 	Previous *string
-	LatestEnounters map[string]struct{}
+	LatestEnounters map[string]Encounter
+	ExploredArea    string
+	CatchAttempts   map[string]int
+	Pokedex         pokedex.Store
+	Confirm         func(question string) bool
+	Profiles        *profile.Manager
+	Profile         profile.Profile
+	// ColorMode is set by --color and wins over the color setting.
+	ColorMode string
+}
+
+// Encounter is a Pokemon found in the explored area and the levels it
+// can appear at there.
+type Encounter struct {
+	MinLevel int
+	MaxLevel int
+}
+
+type pokeball struct {
+	Label string
+	Rate  float64
+}
+
+var pokeballs = map[string]pokeball{
+	"pokeball":   {Label: "Poké Ball", Rate: 1},
+	"greatball":  {Label: "Great Ball", Rate: 1.5},
+	"ultraball":  {Label: "Ultra Ball", Rate: 2},
+	"masterball": {Label: "Master Ball", Rate: 0},
 }
 
 type cliCommand struct {
 	Name        string
 	Description string
 	Callback    func(cfg *Config, args ...string) error
+	// RawArgs passes the arguments as typed instead of lowercased.
+	RawArgs bool
 }
 
-const CacheDir = ".cache"
-
 
 func GetCommands() map[string]cliCommand {
 	return map[string]cliCommand{
@@ -55,18 +84,18 @@ func GetCommands() map[string]cliCommand {
 			Callback:    commandExplore,
 		},
 		"catch": {
-			Name:        "catch <pokemon_name>",
+			Name:        "catch <pokemon_name> [pokeball|greatball|ultraball|masterball]",
 			Description: "Attempt to catch a Pokemon and add it to your Pokedex",
 			Callback:    commandCatch,
 		},
 		"inspect": {
-			Name:        "inspect <pokemon_name>",
+			Name:        "inspect <pokemon_name|id>",
 			Description: "Inspect a Pokemon you have caught and check its stats",
 			Callback:    commandInspect,
 		},
 		"pokedex": {
-			Name:        "pokedex",
-			Description: "Show all pokemon you have caught so far",
+			Name:        "pokedex [grid] [--sort name|id|bst|caught-at] [--type <type>] [--gen <n>] [--min-stat <stat>=<n>]",
+			Description: "List the pokemon you have caught, or show your Pokedex completion grid",
 			Callback:    commandPokedex,
 		},
 		"search": {
@@ -74,7 +103,61 @@ func GetCommands() map[string]cliCommand {
 			Description: "Search <pokemon_name> to see what areas it belonds to",
 			Callback:    commandSearch,
 		},
-
+		"release": {
+			Name:        "release <pokemon_name|id> [--yes]",
+			Description: "Release a Pokemon you have caught back into the wild",
+			Callback:    commandRelease,
+		},
+		"nickname": {
+			Name:        "nickname <pokemon_name|id|nickname> <nickname|--clear>",
+			Description: "Give a Pokemon you have caught a nickname",
+			Callback:    commandNickname,
+			RawArgs:     true,
+		},
+		"export": {
+			Name:        "export [--format json|csv|md] <file>",
+			Description: "Export your Pokedex, the format defaults to the file extension",
+			Callback:    commandExport,
+			RawArgs:     true,
+		},
+		"import": {
+			Name:        "import [--format json|csv] <file>",
+			Description: "Merge an exported Pokedex into yours, reporting conflicts",
+			Callback:    commandImport,
+			RawArgs:     true,
+		},
+		"import-save": {
+			Name:        "import-save <file.sav>",
+			Description: "Import the party and PC boxes of a Red/Blue/Yellow or Gold/Silver/Crystal save",
+			Callback:    commandImportSave,
+			RawArgs:     true,
+		},
+		"showdown": {
+			Name:        "showdown <export <pokemon_name|id>... [--file <file>]|import <file>>",
+			Description: "Export Pokemon as a Showdown team paste, or import one into your Pokedex",
+			Callback:    commandShowdown,
+			RawArgs:     true,
+		},
+		"profile": {
+			Name:        "profile [list|new <name>|use <name>|set <setting> [value]]",
+			Description: "Show your trainer profile and stats, or manage profiles",
+			Callback:    commandProfile,
+		},
+		"undo": {
+			Name:        "undo",
+			Description: "Undo your last catch, release or other change to your Pokedex",
+			Callback:    commandUndo,
+		},
+		"sync": {
+			Name:        "sync",
+			Description: "Download the base stats of every Pokemon to rank stats on cards",
+			Callback:    commandSync,
+		},
+		"cache": {
+			Name:        "cache <stats|list|clear|warm>",
+			Description: "Inspect and manage the API cache (list/clear take an optional prefix, warm takes an endpoint)",
+			Callback:    commandCache,
+		},
 	}
 }
 
@@ -148,13 +231,24 @@ func commandExplore(cfg *Config, args ...string) error {
 	}
 
 	fmt.Println("Found Pokemon:")
-	cfg.LatestEnounters = make(map[string]struct{}) // reset before adding
+	cfg.LatestEnounters = make(map[string]Encounter) // reset before adding
+	cfg.ExploredArea = locationAreaName
+	seen := []pokedex.DexEntry{}
 	for _, encounter := range locationAreaDetails.PokemonEncounters {
 		fmt.Printf(" - %s\n", encounter.Pokemon.Name)
-		cfg.LatestEnounters[encounter.Pokemon.Name] = struct{}{}
+		minLevel, maxLevel := encounter.LevelRange()
+		cfg.LatestEnounters[encounter.Pokemon.Name] = Encounter{MinLevel: minLevel, MaxLevel: maxLevel}
+		seen = append(seen, pokedex.DexEntry{
+			ID:   pokeapi.IDFromURL(encounter.Pokemon.URL),
+			Name: encounter.Pokemon.Name,
+		})
 	}
 
-	return nil
+	if err := cfg.Pokedex.MarkSeen(seen...); err != nil {
+		return fmt.Errorf("could not update pokedex: %w", err)
+	}
+
+	return recordStats(cfg, func(s *profile.Stats) { s.Explored++ })
 }
 
 func commandCatch(cfg *Config, args ...string) error {
@@ -163,7 +257,25 @@ func commandCatch(cfg *Config, args ...string) error {
 	}
 	name := args[0]
 
-	_, inExplored := cfg.LatestEnounters[name]
+	ballName := "pokeball"
+	if cfg.Profile.Dir != "" {
+		settings, err := cfg.Profile.Settings()
+		if err != nil {
+			return err
+		}
+		if settings.Ball != "" {
+			ballName = settings.Ball
+		}
+	}
+	if len(args) > 1 {
+		ballName = args[1]
+	}
+	ball, ok := pokeballs[ballName]
+	if !ok {
+		return fmt.Errorf("unknown ball: %s", ballName)
+	}
+
+	encounter, inExplored := cfg.LatestEnounters[name]
 
 	encounters, err := pokeapi.GetPokemonEncounterAreas(name)
 	if err != nil {
@@ -181,80 +293,256 @@ func commandCatch(cfg *Config, args ...string) error {
 		return err
 	}
 
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemon.Name)
+	fmt.Printf("Throwing a %s at %s...\n", ball.Label, pokemon.Name)
 
-	const catchThreshold = 400
-	roll := rand.Intn(catchThreshold)
+	if cfg.CatchAttempts == nil {
+		cfg.CatchAttempts = make(map[string]int)
+	}
+	cfg.CatchAttempts[name]++
 
-	if roll < pokemon.BaseExperience {
-		fmt.Printf("%s escaped!\n", pokemon.Name)
-		return nil
+	const catchThreshold = 400
+	if ball.Rate > 0 {
+		roll := rand.Intn(int(catchThreshold * ball.Rate))
+		if roll < pokemon.BaseExperience {
+			fmt.Printf("%s escaped!\n", pokemon.Name)
+			if err := cfg.Pokedex.MarkSeen(pokedex.DexEntry{ID: pokemon.ID, Name: pokemon.Name}); err != nil {
+				return err
+			}
+			return recordStats(cfg, func(s *profile.Stats) {
+				s.Thrown++
+				s.Escaped++
+			})
+		}
 	}
 
 	fmt.Printf("%s was caught!\n", pokemon.Name)
 
-	err = pokedex.AddToPokedex(rawData)
+	caught := pokedex.Pokemon{
+		Nature:   pokedex.Natures[rand.Intn(len(pokedex.Natures))],
+		CaughtAt: time.Now(),
+		Attempts: cfg.CatchAttempts[name],
+		Level:    encounterLevel(encounter),
+		Ball:     ball.Label,
+		Shiny:    rand.Intn(shinyOdds) == 0,
+	}
+	if inExplored {
+		caught.Location = cfg.ExploredArea
+	}
+	delete(cfg.CatchAttempts, name)
+
+	if caught.Shiny {
+		fmt.Println("✨ It's shiny! ✨")
+	}
+
+	added, err := pokedex.AddToPokedex(cfg.Pokedex, rawData, caught)
 	if err != nil {
 		return fmt.Errorf("could not add to pokedex: %w", err)
 	}
+	fmt.Printf("%s was added to your collection as #%d\n", added.Species, added.InstanceID)
 
-	return nil
+	return recordStats(cfg, func(s *profile.Stats) {
+		s.Thrown++
+		s.Caught++
+	})
+}
+
+// shinyOdds is the chance, one in shinyOdds, of a caught Pokemon
+// being shiny.
+const shinyOdds = 4096
+
+// Pokemon without wild encounters are gifts or legendaries, which are
+// treated like a starter.
+const giftLevel = 5
+
+func encounterLevel(e Encounter) int {
+	if e.MaxLevel == 0 {
+		return giftLevel
+	}
+	if e.MaxLevel <= e.MinLevel {
+		return e.MinLevel
+	}
+	return e.MinLevel + rand.Intn(e.MaxLevel-e.MinLevel+1)
+}
+
+// describeCatch summarises how a Pokemon was caught. It is empty for
+// entries saved before catch details were recorded.
+func describeCatch(e pokedex.Pokemon) string {
+	if e.CaughtAt.IsZero() {
+		return ""
+	}
+
+	desc := fmt.Sprintf("Lv. %d", e.Level)
+	if e.Nature != "" {
+		desc += ", " + e.Nature + " nature"
+	}
+	desc += ", caught " + e.CaughtAt.Format("2006-01-02 15:04")
+	if e.Location != "" {
+		desc += " at " + e.Location
+	}
+	desc += fmt.Sprintf(" with a %s after %d throw", e.Ball, e.Attempts)
+	if e.Attempts != 1 {
+		desc += "s"
+	}
+	if e.Shiny {
+		desc += " ✨"
+	}
+	return desc
 }
 
 func commandInspect(cfg *Config, args ...string) error {
 	if len(args) == 0 {
-        return errors.New("you must provide a pokemon name to inspect")
-    }
+		return errors.New("you must provide a pokemon name to inspect")
+	}
 
-	name := args[0]
+	ref := args[0]
 
-	caught, err := pokedex.IsCaught(name)
+	caught, ok, err := cfg.Pokedex.Get(ref)
 	if err != nil {
 		return err
 	}
-	if !caught {
-		return fmt.Errorf("%s has not been caught yet", name)
+	if !ok {
+		return fmt.Errorf("%s has not been caught yet", ref)
 	}
 
-	spritePath := filepath.Join(CacheDir, name+".txt")
-	spriteData, err := os.ReadFile(spritePath)
+	lines, err := renderCard(cfg, caught)
 	if err != nil {
-		if os.IsNotExist(err) {
-            return fmt.Errorf("no cached sprite found for %s", name)
-        }
-        return fmt.Errorf("failed to read sprite file: %w", err)
+		return err
 	}
 
-	fmt.Println(string(spriteData))
+	fmt.Println(strings.Join(lines, "\n"))
+	fmt.Println()
+	fmt.Printf("#%d %s", caught.InstanceID, caught.DisplayName())
+	if desc := describeCatch(caught); desc != "" {
+		fmt.Printf(": %s", desc)
+	}
+	fmt.Println()
 	return nil
 }
 
-func commandPokedex(cfg *Config, args ...string) error {
-	caughtFile := filepath.Join(CacheDir, "caught.json")
-	data, err := os.ReadFile(caughtFile)
+func commandRelease(cfg *Config, args ...string) error {
+	if len(args) == 0 {
+		return errors.New("you must provide a pokemon name or id to release")
+	}
+
+	ref := args[0]
+	caught, ok, err := cfg.Pokedex.Get(ref)
 	if err != nil {
-		 if os.IsNotExist(err) {
-            fmt.Println("You haven't caught any Pokémon yet.")
-            return nil
-        }
-        return fmt.Errorf("failed to read caught.json: %w", err)
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is not in your collection", ref)
 	}
 
-	caught := make(map[string]string)
-	if err := json.Unmarshal(data, &caught); err != nil {
-        return fmt.Errorf("failed to parse caught.json: %w", err)
-    }
+	skipConfirm := len(args) > 1 && (args[1] == "--yes" || args[1] == "-y")
+	if !skipConfirm && cfg.Confirm != nil {
+		question := fmt.Sprintf("Release %s #%d? This can be undone with undo.", caught.DisplayName(), caught.InstanceID)
+		if !cfg.Confirm(question) {
+			fmt.Println("Release cancelled.")
+			return nil
+		}
+	}
 
-	if len(caught) == 0 {
-        fmt.Println("You haven't caught any Pokémon yet.")
-        return nil
-    }
+	released, err := cfg.Pokedex.Remove(caught.InstanceID)
+	if err != nil {
+		return err
+	}
 
-	fmt.Println("Your Pokédex:")
-    for _, name := range caught {
-        fmt.Printf(" - %s\n", name)
-    }
+	// the card is shared by the species, keep it while any are left
+	collection, err := cfg.Pokedex.List()
+	if err != nil {
+		return err
+	}
+	remaining := false
+	for _, p := range collection {
+		if p.Species == released.Species {
+			remaining = true
+			break
+		}
+	}
+	if !remaining {
+		if err := pokedex.RemoveCard(released.Species); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s #%d was released. Bye, %s!\n", released.Species, released.InstanceID, released.DisplayName())
+	return recordStats(cfg, func(s *profile.Stats) { s.Released++ })
+}
+
+func commandNickname(cfg *Config, args ...string) error {
+	if len(args) < 2 {
+		return errors.New("you must provide a pokemon and a nickname")
+	}
+
+	// nicknames match case-insensitively, species names are lowercase
+	caught, ok, err := cfg.Pokedex.Get(strings.ToLower(args[0]))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is not in your collection", args[0])
+	}
+
+	nickname := strings.Join(args[1:], " ")
+	if nickname == "--clear" {
+		nickname = ""
+	}
+	// the Pokedex only knows the species you have seen, any species
+	// name would be mistaken for the species once you meet it
+	if nickname != "" {
+		if err := pokedex.ValidateNickname(nickname); err != nil {
+			return err
+		}
+		species, err := isSpeciesName(nickname)
+		if err != nil {
+			return err
+		}
+		if species {
+			return fmt.Errorf("%s is the name of a species, pick another nickname", nickname)
+		}
+	}
+
+	updated, err := cfg.Pokedex.SetNickname(caught.InstanceID, nickname)
+	if err != nil {
+		return err
+	}
 
+	if updated.Nickname == "" {
+		fmt.Printf("%s #%d no longer has a nickname\n", updated.Species, updated.InstanceID)
+		return nil
+	}
+	fmt.Printf("%s #%d is now called %s\n", updated.Species, updated.InstanceID, updated.Nickname)
+	return nil
+}
+
+// isSpeciesName looks name up in the synced stats, or asks pokeapi when
+// stats have not been synced yet.
+func isSpeciesName(name string) (bool, error) {
+	table, err := pokedex.LoadStatTable()
+	if err != nil {
+		return false, err
+	}
+	if table != nil {
+		return table.Has(name), nil
+	}
+
+	_, _, err = pokeapi.GetPokemon(strings.ToLower(name))
+	if errors.Is(err, pokeapi.ErrPokemonNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s against species names: %w", name, err)
+	}
+	return true, nil
+}
+
+func commandUndo(cfg *Config, args ...string) error {
+	op, err := cfg.Pokedex.Undo()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undid %s\n", op.Describe())
 	return nil
 }
 
@@ -282,3 +570,50 @@ func commandSearch(cfg *Config, args ...string) error {
 
 	return nil
 }
+
+func commandCache(cfg *Config, args ...string) error {
+	if len(args) == 0 {
+		return errors.New("you must provide a subcommand: stats, list, clear or warm")
+	}
+
+	prefix := ""
+	if len(args) > 1 {
+		prefix = args[1]
+	}
+
+	switch args[0] {
+	case "stats":
+		stats := pokeapi.CacheStats()
+		fmt.Printf("Entries:   %d\n", stats.Entries)
+		fmt.Printf("Bytes:     %d\n", stats.Bytes)
+		fmt.Printf("Hits:      %d\n", stats.Hits)
+		fmt.Printf("Misses:    %d\n", stats.Misses)
+		fmt.Printf("Evictions: %d\n", stats.Evictions)
+		fmt.Printf("Stale:     %d\n", stats.Stale)
+	case "list":
+		entries := pokeapi.ListCache(pokecache.HasPrefix(prefix))
+		if len(entries) == 0 {
+			fmt.Println("The cache is empty.")
+			return nil
+		}
+		for _, e := range entries {
+			fmt.Printf(" - %s (%d bytes, %s old)\n", e.Key, e.Size, e.Age.Round(time.Second))
+		}
+	case "clear":
+		removed := pokeapi.ClearCache(prefix)
+		fmt.Printf("Removed %d cache entries.\n", removed)
+	case "warm":
+		if prefix == "" {
+			return errors.New("you must provide an endpoint to warm")
+		}
+		url, size, err := pokeapi.Warm(prefix)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cached %s (%d bytes)\n", url, size)
+	default:
+		return fmt.Errorf("unknown cache subcommand: %s", args[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+// fileFormat picks the format from --format, falling back to the file
+// extension.
+func fileFormat(flags map[string]string, path string) string {
+	if format, ok := flags["format"]; ok {
+		return strings.ToLower(format)
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".markdown":
+		return "md"
+	case "":
+		return "json"
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
+
+func commandExport(cfg *Config, args ...string) error {
+	flags, positional, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("you must provide a file to export to")
+	}
+	path := positional[0]
+
+	dex, err := cfg.Pokedex.Dex()
+	if err != nil {
+		return err
+	}
+	collection, err := cfg.Pokedex.List()
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := pokedex.Export(&out, fileFormat(flags, path), dex, collection); err != nil {
+		return err
+	}
+	if err := pokedex.WriteFileAtomic(path, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+
+	fmt.Printf("Exported %d Pokemon and %d species to %s\n", len(collection), len(dex), path)
+	return nil
+}
+
+func commandImport(cfg *Config, args ...string) error {
+	flags, positional, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("you must provide a file to import")
+	}
+	path := positional[0]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	dex, collection, err := pokedex.ParseImport(data, fileFormat(flags, path))
+	if err != nil {
+		return err
+	}
+
+	report, err := cfg.Pokedex.Import(dex, collection)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d Pokemon and %d newly seen species, skipped %d already in your collection\n", report.Added, report.Seen, report.Duplicates)
+	if len(report.Conflicts) > 0 {
+		fmt.Printf("%d conflicts:\n", len(report.Conflicts))
+		for _, conflict := range report.Conflicts {
+			fmt.Printf(" - %s\n", conflict)
+		}
+	}
+	if report.Added > 0 || report.Seen > 0 {
+		fmt.Println("Changed your mind? This can be undone with undo.")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/gamesave"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+func commandImportSave(cfg *Config, args ...string) error {
+	if len(args) == 0 {
+		return errors.New("you must provide a .sav file to import")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	save, err := gamesave.Parse(data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reading the %s save of %s (ID %05d): %d in the party, %d in boxes\n",
+		save.Game, save.Trainer, save.TrainerID, len(save.Party), len(save.Boxes))
+	for _, warning := range save.Warnings {
+		fmt.Printf(" - %s\n", warning)
+	}
+
+	// the save only holds numbers, the names come from the API
+	species := make(map[int]pokedex.DexEntry)
+	moves := make(map[int]string)
+	collection := []pokedex.Pokemon{}
+	for _, m := range save.Mons() {
+		entry, ok := species[m.Species]
+		if !ok {
+			pokemon, raw, err := pokeapi.GetPokemon(strconv.Itoa(m.Species))
+			if err != nil {
+				return fmt.Errorf("species %d: %w", m.Species, err)
+			}
+			info, err := pokedex.ParseSpeciesInfo(raw)
+			if err != nil {
+				return err
+			}
+			entry = pokedex.DexEntry{ID: m.Species, Name: pokemon.Name, Caught: true, Info: &info}
+			species[m.Species] = entry
+		}
+
+		p := pokedex.Pokemon{
+			SpeciesID: m.Species,
+			Species:   entry.Name,
+			Level:     m.Level,
+			OT:        m.OT,
+			OTID:      m.OTID,
+			DVs: pokedex.StatSpread{
+				HP:      m.DVs.HP(),
+				Attack:  m.DVs.Attack,
+				Defense: m.DVs.Defense,
+				SpAtk:   m.DVs.Special,
+				SpDef:   m.DVs.Special,
+				Speed:   m.DVs.Speed,
+			},
+			// shininess only exists from Gen 2 on
+			Shiny: save.Game != gamesave.Gen1 && m.DVs.Shiny(),
+		}
+		if !gamesave.IsDefaultName(m.Nickname, entry.Name) {
+			p.Nickname = m.Nickname
+		}
+		for i, id := range m.Moves {
+			if id == 0 {
+				continue
+			}
+			if _, ok := moves[id]; !ok {
+				name, err := pokeapi.GetMoveName(id)
+				if err != nil {
+					return err
+				}
+				moves[id] = name
+			}
+			p.Moves[i] = moves[id]
+		}
+		collection = append(collection, p)
+	}
+
+	dex := make([]pokedex.DexEntry, 0, len(species))
+	for _, entry := range species {
+		dex = append(dex, entry)
+	}
+	report, err := cfg.Pokedex.Import(dex, collection)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d Pokemon, skipped %d already in your collection\n", report.Added, report.Duplicates)
+	for _, conflict := range report.Conflicts {
+		fmt.Printf(" - %s\n", conflict)
+	}
+	if report.Added > 0 {
+		fmt.Println("Changed your mind? This can be undone with undo.")
+	}
+	return nil
+}
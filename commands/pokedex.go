@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+// parseFlags splits args into --name value (or --name=value) flags and
+// positional arguments.
+func parseFlags(args []string) (map[string]string, []string, error) {
+	flags := make(map[string]string)
+	positional := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("flag --%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		flags[name] = value
+	}
+	return flags, positional, nil
+}
+
+type listOptions struct {
+	sortBy  string
+	typ     string
+	gen     int
+	stat    string
+	minStat int
+}
+
+func parseListOptions(flags map[string]string) (listOptions, error) {
+	opts := listOptions{sortBy: "id"}
+	for name, value := range flags {
+		switch name {
+		case "sort":
+			switch value {
+			case "name", "id", "bst", "caught-at":
+				opts.sortBy = value
+			default:
+				return opts, fmt.Errorf("unknown sort %q, use name, id, bst or caught-at", value)
+			}
+		case "type":
+			if !slices.Contains(pokedex.Types, value) {
+				return opts, fmt.Errorf("unknown type %q", value)
+			}
+			opts.typ = value
+		case "gen":
+			gen, err := strconv.Atoi(value)
+			if err != nil || gen < 1 || gen > len(pokedex.Generations) {
+				return opts, fmt.Errorf("generation must be between 1 and %d", len(pokedex.Generations))
+			}
+			opts.gen = gen
+		case "min-stat":
+			stat, min, ok := strings.Cut(value, "=")
+			if _, known := (pokedex.BaseStats{}).Stat(stat); !ok || !known {
+				return opts, fmt.Errorf("min-stat must look like speed=100, stats are %s", strings.Join(pokedex.StatNames, ", "))
+			}
+			n, err := strconv.Atoi(min)
+			if err != nil {
+				return opts, fmt.Errorf("invalid min-stat value %q", min)
+			}
+			opts.stat, opts.minStat = stat, n
+		default:
+			return opts, fmt.Errorf("unknown flag --%s", name)
+		}
+	}
+	return opts, nil
+}
+
+// speciesInfo returns the stored species data for every caught species,
+// fetching and saving it for species caught before it was recorded.
+func speciesInfo(cfg *Config, dex []pokedex.DexEntry) (map[string]pokedex.SpeciesInfo, error) {
+	infos := make(map[string]pokedex.SpeciesInfo)
+	missing := make(map[string]pokedex.SpeciesInfo)
+	for _, d := range dex {
+		if !d.Caught {
+			continue
+		}
+		if d.Info != nil {
+			infos[d.Name] = *d.Info
+			continue
+		}
+
+		_, raw, err := pokeapi.GetPokemon(d.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch species data for %s: %w", d.Name, err)
+		}
+		info, err := pokedex.ParseSpeciesInfo(raw)
+		if err != nil {
+			return nil, err
+		}
+		infos[d.Name] = info
+		missing[d.Name] = info
+	}
+
+	if len(missing) > 0 {
+		if err := cfg.Pokedex.SetInfo(missing); err != nil {
+			return nil, fmt.Errorf("could not update pokedex: %w", err)
+		}
+	}
+	return infos, nil
+}
+
+func filterPokemon(collection []pokedex.Pokemon, infos map[string]pokedex.SpeciesInfo, opts listOptions) []pokedex.Pokemon {
+	filtered := []pokedex.Pokemon{}
+	for _, p := range collection {
+		info := infos[p.Species]
+		if opts.typ != "" && !info.HasType(opts.typ) {
+			continue
+		}
+		if opts.gen != 0 && !pokedex.Generations[opts.gen-1].Contains(p.SpeciesID) {
+			continue
+		}
+		if opts.stat != "" {
+			if value, _ := info.Stats.Stat(opts.stat); value < opts.minStat {
+				continue
+			}
+		}
+		filtered = append(filtered, p)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		switch opts.sortBy {
+		case "name":
+			if a.Species != b.Species {
+				return a.Species < b.Species
+			}
+		case "bst":
+			ta, tb := infos[a.Species].Stats.Total(), infos[b.Species].Stats.Total()
+			if ta != tb {
+				return ta > tb
+			}
+		case "caught-at":
+			if !a.CaughtAt.Equal(b.CaughtAt) {
+				return a.CaughtAt.Before(b.CaughtAt)
+			}
+		}
+		if a.SpeciesID != b.SpeciesID {
+			return a.SpeciesID < b.SpeciesID
+		}
+		return a.InstanceID < b.InstanceID
+	})
+	return filtered
+}
+
+// renderTable aligns rows into columns, padding by the visible width so
+// cells holding ANSI badges line up.
+func renderTable(header []string, rows [][]string) []string {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = terminal.VisibleWidth(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], terminal.VisibleWidth(cell))
+		}
+	}
+
+	format := func(row []string) string {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-terminal.VisibleWidth(cell)+2))
+			}
+		}
+		return line.String()
+	}
+
+	lines := []string{format(header)}
+	for _, row := range rows {
+		lines = append(lines, format(row))
+	}
+	return lines
+}
+
+func typeBadges(types []string) string {
+	badges := make([]string, len(types))
+	for i, t := range types {
+		badges[i] = pokedex.TypeBadge(t)
+	}
+	return strings.Join(badges, " ")
+}
+
+// printCompletion prints seen and caught totals for gens, and with grid
+// the numbered grid of each generation you have seen something in.
+func printCompletion(dex []pokedex.DexEntry, gens []pokedex.Generation, grid bool) {
+	total := pokedex.Completion{}
+	for _, gen := range gens {
+		c := pokedex.CompletionOf(dex, gen)
+		total.Seen += c.Seen
+		total.Caught += c.Caught
+		total.Total += c.Total
+		if !grid || c.Seen == 0 && len(gens) > 1 {
+			continue
+		}
+
+		fmt.Printf("Generation %d: %d/%d caught (%.1f%%), %d/%d seen (%.1f%%)\n",
+			gen.Number, c.Caught, c.Total, c.CaughtPercent(), c.Seen, c.Total, c.SeenPercent())
+		for _, line := range pokedex.RenderGrid(dex, gen) {
+			fmt.Println(line)
+		}
+		fmt.Println()
+	}
+
+	if grid && len(gens) == 1 {
+		return
+	}
+	label := "National Pokédex"
+	if len(gens) == 1 {
+		label = fmt.Sprintf("Generation %d", gens[0].Number)
+	}
+	fmt.Printf("%s: %d/%d caught (%.1f%%), %d/%d seen (%.1f%%)\n",
+		label, total.Caught, total.Total, total.CaughtPercent(), total.Seen, total.Total, total.SeenPercent())
+}
+
+func commandPokedex(cfg *Config, args ...string) error {
+	flags, positional, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	opts, err := parseListOptions(flags)
+	if err != nil {
+		return err
+	}
+
+	dex, err := cfg.Pokedex.Dex()
+	if err != nil {
+		return err
+	}
+	if len(dex) == 0 {
+		fmt.Println("You haven't seen any Pokémon yet.")
+		return nil
+	}
+
+	gens := pokedex.Generations
+	if opts.gen != 0 {
+		gens = pokedex.Generations[opts.gen-1 : opts.gen]
+	}
+
+	if len(positional) > 0 {
+		if positional[0] != "grid" {
+			return fmt.Errorf("unknown pokedex view: %s", positional[0])
+		}
+		printCompletion(dex, gens, true)
+		return nil
+	}
+
+	collection, err := cfg.Pokedex.List()
+	if err != nil {
+		return err
+	}
+	if len(collection) == 0 {
+		fmt.Println("You haven't caught any Pokémon yet.")
+		return nil
+	}
+
+	infos, err := speciesInfo(cfg, dex)
+	if err != nil {
+		return err
+	}
+
+	filtered := filterPokemon(collection, infos, opts)
+	if len(filtered) == 0 {
+		fmt.Println("No Pokémon match those filters.")
+		return nil
+	}
+
+	rows := make([][]string, 0, len(filtered))
+	for _, p := range filtered {
+		info := infos[p.Species]
+		level, caughtAt := "-", "-"
+		if p.Level > 0 {
+			level = strconv.Itoa(p.Level)
+		}
+		if !p.CaughtAt.IsZero() {
+			caughtAt = p.CaughtAt.Format("2006-01-02")
+		}
+		shiny := ""
+		if p.Shiny {
+			shiny = "✨"
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("#%d", p.InstanceID),
+			fmt.Sprintf("%03d", p.SpeciesID),
+			p.DisplayName(),
+			typeBadges(info.Types),
+			level,
+			strconv.Itoa(info.Stats.Total()),
+			caughtAt,
+			shiny,
+		})
+	}
+
+	for _, line := range renderTable([]string{"ID", "No.", "Name", "Type", "Lv", "BST", "Caught", ""}, rows) {
+		fmt.Println(line)
+	}
+	fmt.Println()
+	printCompletion(dex, gens, false)
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+func TestParseListOptions(t *testing.T) {
+	flags, positional, err := parseFlags([]string{"grid", "--sort", "bst", "--type=fire", "--min-stat", "speed=100"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(positional) != 1 || positional[0] != "grid" {
+		t.Errorf("expected grid as positional argument, got %v", positional)
+	}
+
+	opts, err := parseListOptions(flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := listOptions{sortBy: "bst", typ: "fire", stat: "speed", minStat: 100}
+	if opts != expected {
+		t.Errorf("options do not match. Actual: %+v - vs - Expected: %+v", opts, expected)
+	}
+
+	bad := []map[string]string{
+		{"sort": "weight"},
+		{"type": "sound"},
+		{"gen": "10"},
+		{"min-stat": "luck=10"},
+		{"color": "red"},
+	}
+	for _, flags := range bad {
+		if _, err := parseListOptions(flags); err == nil {
+			t.Errorf("expected an error for %v", flags)
+		}
+	}
+
+	if _, _, err := parseFlags([]string{"--sort"}); err == nil {
+		t.Errorf("expected an error for a flag without a value")
+	}
+}
+
+func TestFilterPokemon(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+	}
+	collection := []pokedex.Pokemon{
+		{InstanceID: 1, SpeciesID: 25, Species: "pikachu", CaughtAt: day(3)},
+		{InstanceID: 2, SpeciesID: 4, Species: "charmander", CaughtAt: day(1)},
+		{InstanceID: 3, SpeciesID: 155, Species: "cyndaquil", CaughtAt: day(2)},
+	}
+	infos := map[string]pokedex.SpeciesInfo{
+		"pikachu":    {Types: []string{"electric"}, Stats: pokedex.BaseStats{HP: 35, Speed: 90}},
+		"charmander": {Types: []string{"fire"}, Stats: pokedex.BaseStats{HP: 39, Speed: 65}},
+		"cyndaquil":  {Types: []string{"fire"}, Stats: pokedex.BaseStats{HP: 39, Speed: 65, Attack: 52}},
+	}
+
+	cases := []struct {
+		opts     listOptions
+		expected []int
+	}{
+		{opts: listOptions{sortBy: "id"}, expected: []int{2, 1, 3}},
+		{opts: listOptions{sortBy: "name"}, expected: []int{2, 3, 1}},
+		{opts: listOptions{sortBy: "bst"}, expected: []int{3, 1, 2}},
+		{opts: listOptions{sortBy: "caught-at"}, expected: []int{2, 3, 1}},
+		{opts: listOptions{sortBy: "id", typ: "fire"}, expected: []int{2, 3}},
+		{opts: listOptions{sortBy: "id", gen: 2}, expected: []int{3}},
+		{opts: listOptions{sortBy: "id", stat: "speed", minStat: 90}, expected: []int{1}},
+	}
+
+	for _, c := range cases {
+		actual := filterPokemon(collection, infos, c.opts)
+		if len(actual) != len(c.expected) {
+			t.Errorf("%+v: len of actual not the same as expected. Actual: %d - vs - Expected: %d", c.opts, len(actual), len(c.expected))
+			continue
+		}
+		for i := range actual {
+			if actual[i].InstanceID != c.expected[i] {
+				t.Errorf("%+v: order does not match. Actual: #%d - vs - Expected: #%d", c.opts, actual[i].InstanceID, c.expected[i])
+			}
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	lines := renderTable([]string{"Name", "Type"}, [][]string{
+		{"pikachu", pokedex.TypeBadge("electric")},
+		{"mew", "psychic"},
+	})
+	if terminal.VisibleWidth(lines[1]) != terminal.VisibleWidth("pikachu  ")+terminal.VisibleWidth(pokedex.TypeBadge("electric")) {
+		t.Errorf("expected badge cell to be padded by visible width, got %q", lines[1])
+	}
+	if lines[2] != "mew      psychic" {
+		t.Errorf("expected aligned columns, got %q", lines[2])
+	}
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/card"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/profile"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/theme"
+)
+
+func commandProfile(cfg *Config, args ...string) error {
+	if cfg.Profiles == nil {
+		return errors.New("profiles are not available")
+	}
+	if len(args) == 0 {
+		return showProfile(cfg)
+	}
+
+	switch args[0] {
+	case "list":
+		names, err := cfg.Profiles.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			marker := " "
+			if name == cfg.Profile.Name {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, name)
+		}
+		return nil
+	case "new":
+		if len(args) < 2 {
+			return errors.New("usage: profile new <name>")
+		}
+		p, err := cfg.Profiles.Create(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created profile %s, switch to it with: profile use %s\n", p.Name, p.Name)
+		return nil
+	case "use":
+		if len(args) < 2 {
+			return errors.New("usage: profile use <name>")
+		}
+		p, err := cfg.Profiles.Open(args[1])
+		if err != nil {
+			return err
+		}
+		// upgrade first so a save that can't be read is never made current
+		if _, err := p.Store().Upgrade(); err != nil {
+			return err
+		}
+		if err := cfg.Profiles.SetCurrent(p.Name); err != nil {
+			return err
+		}
+		if err := cfg.UseProfile(p); err != nil {
+			return err
+		}
+		fmt.Printf("Switched to profile %s\n", p.Name)
+		return nil
+	case "set":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: profile set <setting> [value], settings: %v", profile.SettingNames())
+		}
+		return setSetting(cfg, args[1], args[2:]...)
+	}
+	return fmt.Errorf("unknown profile command: %s", args[0])
+}
+
+// UseProfile points the config at a profile's save and forgets progress
+// that belonged to the previous trainer.
+func (cfg *Config) UseProfile(p profile.Profile) error {
+	cfg.Profile = p
+	cfg.Pokedex = p.Store()
+	cfg.CatchAttempts = nil
+	return cfg.ApplyStyle()
+}
+
+// ApplyStyle sets how output is styled: color from --color, or else the
+// color setting of the current profile, and the theme setting.
+func (cfg *Config) ApplyStyle() error {
+	var settings profile.Settings
+	if cfg.Profile.Dir != "" {
+		var err error
+		if settings, err = cfg.Profile.Settings(); err != nil {
+			return err
+		}
+	}
+	mode := cfg.ColorMode
+	if mode == "" {
+		mode = settings.Color
+	}
+
+	enabled, err := terminal.ColorEnabled(mode, os.Getenv, terminal.IsTerminal(os.Stdout))
+	if err != nil {
+		return err
+	}
+	depth := terminal.NoColor
+	if enabled {
+		// a terminal that advertises no colors still gets the basic
+		// ones when they are asked for
+		depth = max(terminal.DetectDepth(os.Getenv), terminal.Color16)
+	}
+	terminal.SetDepth(depth)
+
+	name := settings.Theme
+	if name == "" {
+		name = theme.DefaultName
+	}
+	t, err := theme.Load(name)
+	if err != nil {
+		return err
+	}
+	theme.Set(t)
+	return nil
+}
+
+func showProfile(cfg *Config) error {
+	stats, err := cfg.Profile.Stats()
+	if err != nil {
+		return err
+	}
+	settings, err := cfg.Profile.Settings()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Trainer: %s\n", cfg.Profile.Name)
+	fmt.Printf("Areas explored: %d\n", stats.Explored)
+	fmt.Printf("Balls thrown: %d (%d caught, %d escaped, %.0f%% catch rate)\n", stats.Thrown, stats.Caught, stats.Escaped, stats.CatchRate())
+	fmt.Printf("Released: %d\n", stats.Released)
+	for _, name := range profile.SettingNames() {
+		value, _ := settings.Get(name)
+		if value == "" {
+			value = "(default)"
+		}
+		fmt.Printf("%s: %s\n", name, value)
+	}
+	return nil
+}
+
+func setSetting(cfg *Config, key string, values ...string) error {
+	value := ""
+	if len(values) > 0 {
+		value = values[0]
+	}
+	if err := validateSetting(key, value); err != nil {
+		return err
+	}
+
+	settings, err := cfg.Profile.Settings()
+	if err != nil {
+		return err
+	}
+	if err := settings.Set(key, value); err != nil {
+		return err
+	}
+	if err := cfg.Profile.SaveSettings(settings); err != nil {
+		return err
+	}
+	if key == "color" || key == "theme" {
+		if err := cfg.ApplyStyle(); err != nil {
+			return err
+		}
+	}
+
+	if value == "" {
+		fmt.Printf("%s reset to the default\n", key)
+		return nil
+	}
+	fmt.Printf("%s set to %s\n", key, value)
+	return nil
+}
+
+// recordStats updates the statistics of the current profile, if any.
+func recordStats(cfg *Config, fn func(s *profile.Stats)) error {
+	if cfg.Profile.Dir == "" {
+		return nil
+	}
+	return cfg.Profile.UpdateStats(fn)
+}
+
+func validateSetting(key, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch key {
+	case "ball":
+		if _, ok := pokeballs[value]; !ok {
+			return fmt.Errorf("unknown ball: %s", value)
+		}
+	case "sprite":
+		if !slices.Contains(card.SpriteNames, value) {
+			return fmt.Errorf("sprite must be one of %s", strings.Join(card.SpriteNames, ", "))
+		}
+	case "scaling":
+		if !slices.Contains(card.ScalerNames, value) {
+			return fmt.Errorf("scaling must be one of %s", strings.Join(card.ScalerNames, ", "))
+		}
+	case "color":
+		if !slices.Contains(terminal.ColorModes, value) {
+			return fmt.Errorf("color must be one of %s", strings.Join(terminal.ColorModes, ", "))
+		}
+	case "theme":
+		if !slices.Contains(theme.Names(), value) {
+			return fmt.Errorf("theme must be one of %s", strings.Join(theme.Names(), ", "))
+		}
+	case "card-cache":
+		if value != "on" && value != "off" {
+			return errors.New("card-cache must be on or off")
+		}
+	}
+	return nil
+}
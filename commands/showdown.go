@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+func commandShowdown(cfg *Config, args ...string) error {
+	if len(args) == 0 {
+		return errors.New("usage: showdown export <pokemon_name|id>... [--file <file>] or showdown import <file>")
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "export":
+		return showdownExport(cfg, args[1:])
+	case "import":
+		return showdownImport(cfg, args[1:])
+	}
+	return fmt.Errorf("unknown showdown command: %s", args[0])
+}
+
+func showdownExport(cfg *Config, args []string) error {
+	flags, refs, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return errors.New("you must pick the pokemon to export")
+	}
+
+	team := []pokedex.Pokemon{}
+	for _, ref := range refs {
+		p, ok, err := cfg.Pokedex.Get(strings.ToLower(ref))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s is not in your collection", ref)
+		}
+		team = append(team, p)
+	}
+
+	paste := pokedex.FormatShowdown(team)
+	path, ok := flags["file"]
+	if !ok {
+		fmt.Print(paste)
+		return nil
+	}
+	if err := pokedex.WriteFileAtomic(path, []byte(paste), 0644); err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	fmt.Printf("Exported %d Pokemon to %s\n", len(team), path)
+	return nil
+}
+
+func showdownImport(cfg *Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("you must provide a paste file to import")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	team, err := pokedex.ParseShowdown(string(data))
+	if err != nil {
+		return err
+	}
+
+	// validate the whole team before importing any of it
+	dex := []pokedex.DexEntry{}
+	for i, p := range team {
+		species, raw, err := pokeapi.GetPokemon(p.Species)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Species, err)
+		}
+		if err := pokedex.ValidateShowdown(p, raw); err != nil {
+			return err
+		}
+		info, err := pokedex.ParseSpeciesInfo(raw)
+		if err != nil {
+			return err
+		}
+
+		team[i].SpeciesID = species.ID
+		dex = append(dex, pokedex.DexEntry{ID: species.ID, Name: p.Species, Caught: true, Info: &info})
+	}
+
+	report, err := cfg.Pokedex.Import(dex, team)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d Pokemon, skipped %d already in your collection\n", report.Added, report.Duplicates)
+	for _, conflict := range report.Conflicts {
+		fmt.Printf(" - %s\n", conflict)
+	}
+	return nil
+}
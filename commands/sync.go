@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+// syncWorkers is how many Pokemon are fetched at once while syncing.
+const syncWorkers = 8
+
+// commandSync downloads the base stats of every species so cards can
+// rank stats against all Pokemon.
+func commandSync(cfg *Config, args ...string) error {
+	list, err := pokeapi.ListPokemon()
+	if err != nil {
+		return err
+	}
+
+	// forms such as megas have ids past the last species and share their
+	// species' name with a suffix, so only species are ranked
+	last := pokedex.Generations[len(pokedex.Generations)-1].Last
+	names := []string{}
+	for _, p := range list {
+		if id := pokeapi.IDFromURL(p.URL); id >= 1 && id <= last {
+			names = append(names, p.Name)
+		}
+	}
+	fmt.Printf("Syncing the base stats of %d Pokémon...\n", len(names))
+
+	table, err := fetchStats(names)
+	if err != nil {
+		return err
+	}
+	if err := pokedex.SaveStatTable(table); err != nil {
+		return err
+	}
+	fmt.Printf("Synced the base stats of %d Pokémon, cards now rank stats against them\n", len(table))
+	return nil
+}
+
+func fetchStats(names []string) (pokedex.StatTable, error) {
+	jobs := make(chan string)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	table := make(pokedex.StatTable, len(names))
+
+	for range syncWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				_, raw, err := pokeapi.GetPokemon(name)
+				var info pokedex.SpeciesInfo
+				if err == nil {
+					info, err = pokedex.ParseSpeciesInfo(raw)
+				}
+				// the full responses are large and only the stats are kept
+				pokeapi.ForgetURL(pokeapi.PokemonURL(name))
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("failed to sync %s: %w", name, err)
+				}
+				if err == nil {
+					table[name] = info.Stats
+					if len(table)%100 == 0 {
+						fmt.Printf("Synced %d/%d\n", len(table), len(names))
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, name := range names {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- name
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return table, nil
+}
@@ -0,0 +1,37 @@
+package card
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The render cache stores finished cards as text, with the renderer key
+// on the first line. A card rendered with other settings, or an old
+// pre-rendered file without a key, is a miss.
+const cachePrefix = "# card "
+
+// ReadCached returns the cached card at path if it was rendered with key.
+func ReadCached(path, key string) ([]string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	first, rest, _ := strings.Cut(string(data), "\n")
+	if first != cachePrefix+key {
+		return nil, false
+	}
+	return strings.Split(strings.TrimSuffix(rest, "\n"), "\n"), true
+}
+
+func WriteCached(path, key string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data := cachePrefix + key + "\n" + strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to cache card: %w", err)
+	}
+	return nil
+}
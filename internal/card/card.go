@@ -0,0 +1,197 @@
+// Package card renders the Pokedex card shown by inspect from stored
+// species data, so layout changes apply without catching again.
+package card
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/theme"
+)
+
+const (
+	reset         = "\x1b[0m"
+	spriteColumns = 48
+	// bars are barWidth cells wide for the highest possible base stat
+	barWidth = 16
+	maxStat  = 255
+	// minSpriteColumns is the narrowest sprite shown beside the data,
+	// below it the sprite goes above the data instead.
+	minSpriteColumns = 24
+	gap              = 2
+)
+
+// Card is the data shown for a species.
+type Card struct {
+	ID     int
+	Name   string
+	Info   pokedex.SpeciesInfo
+	Sprite image.Image
+}
+
+// Renderer lays a card out as terminal lines. Width is the number of
+// terminal columns to fit in, 0 for no limit. The data is styled with
+// Theme at Depth, and cards at NoColor have no escape sequences at all.
+// Stats are ranked against Ranking when stats have been synced.
+type Renderer struct {
+	Sprite        SpriteRenderer
+	Scale         Scaler
+	SpriteColumns int
+	Width         int
+	Theme         theme.Theme
+	Depth         terminal.Depth
+	Ranking       pokedex.StatTable
+}
+
+func NewRenderer(sprite SpriteRenderer) *Renderer {
+	return &Renderer{
+		Sprite:        sprite,
+		Scale:         Nearest,
+		SpriteColumns: spriteColumns,
+		Theme:         theme.Current(),
+		Depth:         terminal.CurrentDepth(),
+	}
+}
+
+// layoutVersion changes whenever the card looks different, so cached
+// renders from older versions are not reused.
+const layoutVersion = 5
+
+// Key identifies the output of the renderer for the render cache.
+func (r *Renderer) Key() string {
+	return fmt.Sprintf("v%d-%s-%s-%d-%d-%s-%d-%d", layoutVersion, r.sprite().Name(), r.Scale.Name, r.SpriteColumns, r.Width, r.Theme.Name, r.Depth, len(r.Ranking))
+}
+
+// sprite is the sprite renderer in use, which is ascii for plain cards
+// unless the chosen one is already free of escapes.
+func (r *Renderer) sprite() SpriteRenderer {
+	if r.Depth != terminal.NoColor {
+		return r.Sprite
+	}
+	switch r.Sprite.(type) {
+	case braille, ascii:
+		return r.Sprite
+	}
+	return ascii{}
+}
+
+// Render puts the sprite on the left and the species data on the right,
+// or the sprite above the data when the terminal is too narrow for both
+// or the sprite is drawn in a way that moves the cursor below it.
+func (r *Renderer) Render(c Card) []string {
+	info := r.details(c)
+	columns, stacked := r.layout(info)
+
+	var sprite []string
+	if c.Sprite != nil {
+		renderer := r.sprite()
+		if inline, ok := renderer.(inlineImage); ok && inline.below {
+			stacked = true
+		}
+		sprite = renderer.Render(c.Sprite, columns, r.Scale)
+	}
+	if stacked {
+		return append(sprite, info...)
+	}
+
+	var lines []string
+	for i := 0; i < max(len(sprite), len(info)); i++ {
+		left := strings.Repeat(" ", columns)
+		if i < len(sprite) {
+			left = sprite[i]
+		}
+		var right string
+		if i < len(info) {
+			right = info[i]
+		}
+		lines = append(lines, left+strings.Repeat(" ", gap)+right)
+	}
+	return lines
+}
+
+// layout picks the sprite width and whether the sprite goes above the
+// data.
+func (r *Renderer) layout(info []string) (int, bool) {
+	if r.Width <= 0 {
+		return r.SpriteColumns, false
+	}
+
+	infoWidth := 0
+	for _, line := range info {
+		infoWidth = max(infoWidth, terminal.VisibleWidth(line))
+	}
+	if r.Width-gap-infoWidth >= min(minSpriteColumns, r.SpriteColumns) {
+		return min(r.SpriteColumns, r.Width-gap-infoWidth), false
+	}
+	return max(min(r.SpriteColumns, r.Width), 1), true
+}
+
+func (r *Renderer) details(c Card) []string {
+	stats := c.Info.Stats
+	return []string{
+		r.Theme.Header.Paint(r.Depth, "═════════ POKÉDEX DATA ═════════"),
+		r.field("Name:", c.Name),
+		r.field("ID:", fmt.Sprintf("#%d", c.ID)),
+		r.field("Type:", r.formatTypes(c.Info.Types)),
+		r.field("Height:", fmt.Sprintf("%.2f m", c.Info.Height)),
+		r.field("Weight:", fmt.Sprintf("%.1f kg", c.Info.Weight)),
+		"",
+		r.Theme.Header.Paint(r.Depth, "═════════ BASE STATS ══════════"),
+		r.field("HP:", r.stat("hp", stats.HP)),
+		r.field("Attack:", r.stat("attack", stats.Attack)),
+		r.field("Defense:", r.stat("defense", stats.Defense)),
+		r.field("Sp.Atk:", r.stat("sp-atk", stats.SpAtk)),
+		r.field("Sp.Def:", r.stat("sp-def", stats.SpDef)),
+		r.field("Speed:", r.stat("speed", stats.Speed)),
+		r.field("Total:", fmt.Sprintf("%3d %s%s", stats.Total(), strings.Repeat(" ", barWidth), r.percentile("bst", stats.Total()))),
+	}
+}
+
+// stat shows a base stat with its bar, and its percentile when stats
+// have been synced.
+func (r *Renderer) stat(name string, value int) string {
+	return fmt.Sprintf("%3d %s%s", value, r.bar(value), r.percentile(name, value))
+}
+
+func (r *Renderer) percentile(name string, value int) string {
+	p, ok := r.Ranking.Percentile(name, value)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("  p%d", p)
+}
+
+// eighths are the partial blocks that end a bar.
+var eighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// bar draws a base stat out of maxStat, to an eighth of a cell, followed
+// by the empty rest of the track.
+func (r *Renderer) bar(value int) string {
+	units := min(max(value, 0), maxStat) * barWidth * 8 / maxStat
+	filled := strings.Repeat("█", units/8) + eighths[units%8]
+	cells := (units + 7) / 8
+
+	var b strings.Builder
+	if filled != "" {
+		b.WriteString(r.Theme.StatStyle(value).Paint(r.Depth, filled))
+	}
+	if cells < barWidth {
+		b.WriteString(r.Theme.Seen.Paint(r.Depth, strings.Repeat("░", barWidth-cells)))
+	}
+	return b.String()
+}
+
+func (r *Renderer) field(name string, value any) string {
+	return fmt.Sprintf("%s%s%v", r.Theme.Label.Paint(r.Depth, name), strings.Repeat(" ", 10-len(name)), value)
+}
+
+func (r *Renderer) formatTypes(types []string) string {
+	badges := make([]string, len(types))
+	for i, t := range types {
+		badges[i] = r.Theme.Badge(r.Depth, t)
+	}
+	return strings.Join(badges, " | ")
+}
@@ -0,0 +1,213 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/theme"
+)
+
+func testSprite() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 1; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	return img
+}
+
+func TestRender(t *testing.T) {
+	c := Card{
+		ID:   25,
+		Name: "pikachu",
+		Info: pokedex.SpeciesInfo{
+			Types:  []string{"electric"},
+			Stats:  pokedex.BaseStats{HP: 35, Attack: 55, Defense: 40, SpAtk: 50, SpDef: 50, Speed: 90},
+			Height: 0.4,
+			Weight: 6,
+		},
+		Sprite: testSprite(),
+	}
+
+	r := &Renderer{Sprite: halfBlock{terminal.TrueColor}, Scale: Nearest, SpriteColumns: 4, Theme: theme.Current(), Depth: terminal.TrueColor}
+	lines := r.Render(c)
+	if len(lines) != 15 {
+		t.Fatalf("len of actual not the same as expected. Actual: %d - vs - Expected: %d", len(lines), 15)
+	}
+
+	expected := []string{"pikachu", "#25", theme.Current().Badge(terminal.TrueColor, "electric"), "0.40 m", "6.0 kg", "90", "320"}
+	card := strings.Join(lines, "\n")
+	for _, e := range expected {
+		if !strings.Contains(card, e) {
+			t.Errorf("expected the card to contain %q", e)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "\x1b[38;2;255;0;0m\x1b[49m▀") {
+		t.Errorf("expected the sprite to start with a red upper half-block, got %q", lines[0])
+	}
+
+	plain := *r
+	plain.Depth = terminal.NoColor
+	for _, line := range plain.Render(c) {
+		if strings.Contains(line, "\x1b") {
+			t.Errorf("expected no escapes on a plain card, got %q", line)
+		}
+	}
+	if plain.Key() == r.Key() {
+		t.Errorf("expected plain and styled cards to be cached apart")
+	}
+
+	c.Sprite = nil
+	if lines := r.Render(c); len(lines) != 15 {
+		t.Errorf("expected a card without a sprite to still show its data, got %d lines", len(lines))
+	}
+}
+
+func TestStatBars(t *testing.T) {
+	r := &Renderer{Theme: theme.Current(), Depth: terminal.NoColor}
+	cases := []struct {
+		value    int
+		expected string
+	}{
+		{value: 0, expected: strings.Repeat("░", 16)},
+		{value: 35, expected: "██▏" + strings.Repeat("░", 13)},
+		{value: 128, expected: "████████" + strings.Repeat("░", 8)},
+		{value: 255, expected: strings.Repeat("█", 16)},
+		{value: 300, expected: strings.Repeat("█", 16)},
+	}
+	for _, c := range cases {
+		if actual := r.bar(c.value); actual != c.expected {
+			t.Errorf("bar of %d does not match. Actual: %q - vs - Expected: %q", c.value, actual, c.expected)
+		}
+	}
+
+	r.Depth = terminal.TrueColor
+	if actual := terminal.VisibleWidth(r.bar(35)); actual != 16 {
+		t.Errorf("width does not match. Actual: %d - vs - Expected: %d", actual, 16)
+	}
+	if !strings.HasPrefix(r.bar(35), theme.Current().StatStyle(35).Paint(terminal.TrueColor, "██▏")) {
+		t.Errorf("expected the bar in the color of its stat, got %q", r.bar(35))
+	}
+
+	if actual := r.stat("hp", 35); actual != " 35 "+r.bar(35) {
+		t.Errorf("expected no percentile before syncing, got %q", actual)
+	}
+	r.Ranking = pokedex.StatTable{"pikachu": {HP: 35}, "snorlax": {HP: 160}}
+	if actual := r.stat("hp", 35); !strings.HasSuffix(actual, "  p25") {
+		t.Errorf("expected the percentile after the bar, got %q", actual)
+	}
+}
+
+func TestRenderLayout(t *testing.T) {
+	c := Card{
+		ID:     25,
+		Name:   "pikachu",
+		Info:   pokedex.SpeciesInfo{Types: []string{"electric"}},
+		Sprite: checkerSprite(16),
+	}
+	r := NewRenderer(halfBlock{terminal.TrueColor})
+	info := r.details(c)
+	infoWidth := 0
+	for _, line := range info {
+		infoWidth = max(infoWidth, terminal.VisibleWidth(line))
+	}
+
+	cases := []struct {
+		width   int
+		columns int
+		stacked bool
+	}{
+		{width: 0, columns: 48, stacked: false},
+		{width: 200, columns: 48, stacked: false},
+		{width: infoWidth + gap + 30, columns: 30, stacked: false},
+		{width: infoWidth + gap + 10, columns: infoWidth + gap + 10, stacked: true},
+		{width: 20, columns: 20, stacked: true},
+	}
+
+	for _, tc := range cases {
+		r.Width = tc.width
+		lines := r.Render(c)
+		rows := tc.columns / 2
+		if tc.stacked {
+			if len(lines) != rows+len(info) {
+				t.Errorf("width %d: len of actual not the same as expected. Actual: %d - vs - Expected: %d", tc.width, len(lines), rows+len(info))
+			}
+			continue
+		}
+		if len(lines) != max(rows, len(info)) {
+			t.Errorf("width %d: len of actual not the same as expected. Actual: %d - vs - Expected: %d", tc.width, len(lines), max(rows, len(info)))
+		}
+		for _, line := range lines {
+			if w := terminal.VisibleWidth(line); tc.width > 0 && w > tc.width {
+				t.Errorf("width %d: line is %d columns wide", tc.width, w)
+			}
+		}
+		// the data lines up after the sprite, even past its last row
+		for _, i := range []int{0, len(info) - 1} {
+			if terminal.VisibleWidth(lines[i])-terminal.VisibleWidth(info[i]) != tc.columns+gap {
+				t.Errorf("width %d: expected the data to start at column %d, got %q", tc.width, tc.columns+gap, lines[i])
+			}
+		}
+	}
+	// the cursor ends up below a Sixel image, so the data goes under it
+	r.Sprite = newSixel(halfBlock{terminal.TrueColor})
+	r.Width = 200
+	lines := r.Render(c)
+	if len(lines) != 1+len(info) || lines[1] != info[0] {
+		t.Errorf("expected the data below the sixel image, got %q", lines)
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pikachu.txt")
+	lines := []string{"line one", "line two"}
+
+	if _, ok := ReadCached(path, "v1"); ok {
+		t.Errorf("expected a miss before anything is cached")
+	}
+	if err := WriteCached(path, "v1", lines); err != nil {
+		t.Fatal(err)
+	}
+
+	cached, ok := ReadCached(path, "v1")
+	if !ok || strings.Join(cached, "\n") != strings.Join(lines, "\n") {
+		t.Errorf("expected the cached card, got %q %v", cached, ok)
+	}
+	if _, ok := ReadCached(path, "v2"); ok {
+		t.Errorf("expected a miss for a card rendered with other settings")
+	}
+
+	os.WriteFile(path, []byte("old pre-rendered card\n"), 0644)
+	if _, ok := ReadCached(path, "v1"); ok {
+		t.Errorf("expected a miss for a card without a key")
+	}
+}
+
+func TestLoadSpriteFromCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pikachu.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, testSprite()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// an empty url would fail to download, so this must come from disk
+	img, err := LoadSprite("", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 4 {
+		t.Errorf("width does not match. Actual: %d - vs - Expected: %d", img.Bounds().Dx(), 4)
+	}
+}
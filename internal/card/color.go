@@ -0,0 +1,15 @@
+package card
+
+import (
+	"image/color"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+func toRGB(c color.Color) (terminal.RGB, bool) {
+	r, g, b, a := c.RGBA()
+	return terminal.RGB{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}, a > alphaThreshold
+}
+
+// alphaThreshold is the 16-bit alpha above which a pixel is drawn.
+const alphaThreshold = 32768
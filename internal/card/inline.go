@@ -0,0 +1,246 @@
+package card
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"slices"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+const (
+	// cellWidth and cellHeight are the assumed size of a terminal cell in
+	// pixels, used to scale sprites that are drawn as real images.
+	cellWidth  = 8
+	cellHeight = 16
+	// kittyChunk is the largest payload the Kitty protocol takes per
+	// escape sequence.
+	kittyChunk = 4096
+)
+
+// inlineImage draws the real sprite with a terminal graphics protocol.
+// When the protocol leaves the cursor where it was, the image covers the
+// cells of the lines after it and the rest of the card is printed beside
+// it as usual.
+type inlineImage struct {
+	name      string
+	encode    func(img image.Image, columns, rows int, scale Scaler) (string, error)
+	supported func(getenv func(string) string) bool
+	fallback  SpriteRenderer
+	// below is set when the cursor ends up under the image, so nothing
+	// can be printed beside it
+	below bool
+}
+
+func (i inlineImage) Name() string {
+	return i.name
+}
+
+// Render falls back to half-blocks if the image can't be encoded.
+func (i inlineImage) Render(img image.Image, columns int, scale Scaler) []string {
+	rows := max(columns/2, 1)
+	sequence, err := i.encode(img, columns, rows, scale)
+	if err != nil {
+		return i.fallback.Render(img, columns, scale)
+	}
+
+	if i.below {
+		return []string{sequence}
+	}
+
+	lines := make([]string, rows)
+	blank := strings.Repeat(" ", columns)
+	for row := range lines {
+		lines[row] = blank
+	}
+	lines[0] = sequence + blank
+	return lines
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode sprite: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// kittySequence transmits and shows a PNG with the Kitty graphics
+// protocol, in chunks, without moving the cursor.
+func kittySequence(data []byte, columns, rows int) string {
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	for start := 0; start < len(payload) || start == 0; start += kittyChunk {
+		end := min(start+kittyChunk, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if start == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", columns, rows, more, payload[start:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, payload[start:end])
+		}
+	}
+	return b.String()
+}
+
+// iterm2Sequence shows a PNG with the iTerm2 inline image protocol,
+// without moving the cursor.
+func iterm2Sequence(data []byte, columns, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1;doNotMoveCursor=1:%s\a",
+		len(data), columns, rows, base64.StdEncoding.EncodeToString(data))
+}
+
+// sixelSequence encodes an image as Sixel graphics, with colors from the
+// xterm-256 palette. Transparent pixels are left alone.
+func sixelSequence(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// palette index of every pixel, -1 where transparent
+	pixels := make([]int, width*height)
+	used := []int{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c, opaque := toRGB(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			index := -1
+			if opaque {
+				index = terminal.Nearest256(c)
+				if !slices.Contains(used, index) {
+					used = append(used, index)
+				}
+			}
+			pixels[y*width+x] = index
+		}
+	}
+	slices.Sort(used)
+
+	var b strings.Builder
+	// P2=1 keeps unset pixels transparent
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for _, index := range used {
+		c := terminal.Palette256(index)
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", index, int(c.R)*100/255, int(c.G)*100/255, int(c.B)*100/255)
+	}
+
+	for band := 0; band < height; band += 6 {
+		first := true
+		for _, index := range used {
+			row := make([]byte, width)
+			lit := false
+			for x := 0; x < width; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = byte(63 + bits)
+				lit = lit || bits != 0
+			}
+			if !lit {
+				continue
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d%s", index, sixelRunLength(row))
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// sixelRunLength compresses repeated sixels as !<count><sixel>.
+func sixelRunLength(row []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(&b, "!%d%c", n, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func scaleToCells(img image.Image, columns, rows int, scale Scaler) image.Image {
+	return scale.Scale(img, columns*cellWidth, rows*cellHeight)
+}
+
+// sixelTerms and sixelPrograms are the TERM families and TERM_PROGRAM
+// values of terminals that draw Sixel graphics.
+var (
+	sixelTerms    = []string{"foot", "mlterm", "contour", "yaft"}
+	sixelPrograms = []string{"WezTerm", "mintty", "iTerm.app"}
+)
+
+func newKitty(fallback SpriteRenderer) inlineImage {
+	return inlineImage{
+		name: "kitty",
+		encode: func(img image.Image, columns, rows int, scale Scaler) (string, error) {
+			data, err := encodePNG(scaleToCells(img, columns, rows, scale))
+			if err != nil {
+				return "", err
+			}
+			return kittySequence(data, columns, rows), nil
+		},
+		supported: func(getenv func(string) string) bool {
+			return getenv("KITTY_WINDOW_ID") != "" || getenv("TERM") == "xterm-kitty" ||
+				getenv("TERM_PROGRAM") == "WezTerm" || getenv("TERM_PROGRAM") == "ghostty"
+		},
+		fallback: fallback,
+	}
+}
+
+func newITerm2(fallback SpriteRenderer) inlineImage {
+	return inlineImage{
+		name: "iterm2",
+		encode: func(img image.Image, columns, rows int, scale Scaler) (string, error) {
+			data, err := encodePNG(scaleToCells(img, columns, rows, scale))
+			if err != nil {
+				return "", err
+			}
+			return iterm2Sequence(data, columns, rows), nil
+		},
+		supported: func(getenv func(string) string) bool {
+			return getenv("TERM_PROGRAM") == "iTerm.app" || getenv("LC_TERMINAL") == "iTerm2" ||
+				getenv("TERM_PROGRAM") == "WezTerm"
+		},
+		fallback: fallback,
+	}
+}
+
+func newSixel(fallback SpriteRenderer) inlineImage {
+	return inlineImage{
+		name: "sixel",
+		encode: func(img image.Image, columns, rows int, scale Scaler) (string, error) {
+			return sixelSequence(scaleToCells(img, columns, rows, scale)), nil
+		},
+		// there is no variable for Sixel support, so only terminals known
+		// to draw it are trusted, and not through a multiplexer
+		supported: func(getenv func(string) string) bool {
+			if getenv("TMUX") != "" || getenv("STY") != "" {
+				return false
+			}
+			term := getenv("TERM")
+			return slices.Contains(sixelTerms, strings.SplitN(term, "-", 2)[0]) ||
+				slices.Contains(sixelPrograms, getenv("TERM_PROGRAM"))
+		},
+		fallback: fallback,
+		below:    true,
+	}
+}
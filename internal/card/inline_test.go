@@ -0,0 +1,110 @@
+package card
+
+import (
+	"encoding/base64"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestKittySequence(t *testing.T) {
+	small := kittySequence([]byte("png"), 4, 2)
+	expected := "\x1b_Ga=T,f=100,q=2,C=1,c=4,r=2,m=0;cG5n\x1b\\"
+	if small != expected {
+		t.Errorf("sequence does not match. Actual: %q - vs - Expected: %q", small, expected)
+	}
+
+	// 3 * 4096 bytes encode to 4 chunks of base64
+	large := kittySequence(make([]byte, 3*kittyChunk), 4, 2)
+	chunks := strings.Split(strings.TrimSuffix(large, "\x1b\\"), "\x1b\\")
+	if len(chunks) != 4 {
+		t.Fatalf("len of actual not the same as expected. Actual: %d - vs - Expected: %d", len(chunks), 4)
+	}
+	if !strings.HasPrefix(chunks[0], "\x1b_Ga=T,f=100,q=2,C=1,c=4,r=2,m=1;") {
+		t.Errorf("unexpected first chunk %q", chunks[0][:40])
+	}
+	if !strings.HasPrefix(chunks[1], "\x1b_Gm=1;") || !strings.HasPrefix(chunks[3], "\x1b_Gm=0;") {
+		t.Errorf("expected middle chunks with m=1 and a last one with m=0")
+	}
+}
+
+func TestITerm2Sequence(t *testing.T) {
+	actual := iterm2Sequence([]byte("png"), 4, 2)
+	expected := "\x1b]1337;File=inline=1;size=3;width=4;height=2;preserveAspectRatio=1;doNotMoveCursor=1:" + base64.StdEncoding.EncodeToString([]byte("png")) + "\a"
+	if actual != expected {
+		t.Errorf("sequence does not match. Actual: %q - vs - Expected: %q", actual, expected)
+	}
+}
+
+func TestSixelSequence(t *testing.T) {
+	// a 5x7 image: red top-left pixel, a full white column at x=4, and
+	// nothing else, so it spans two bands
+	img := image.NewRGBA(image.Rect(0, 0, 5, 7))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	for y := 0; y < 7; y++ {
+		img.Set(4, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+
+	expected := "\x1bP0;1;0q\"1;1;5;7" +
+		"#196;2;100;0;0#231;2;100;100;100" +
+		"#196@!4?$#231!4?~-" +
+		"#231!4?@-" +
+		"\x1b\\"
+	if actual := sixelSequence(img); actual != expected {
+		t.Errorf("sequence does not match.\nActual:   %q\nExpected: %q", actual, expected)
+	}
+}
+
+func TestSixelRunLength(t *testing.T) {
+	if actual := sixelRunLength([]byte("???????@@~")); actual != "!7?@@~" {
+		t.Errorf("run length does not match. Actual: %q - vs - Expected: %q", actual, "!7?@@~")
+	}
+}
+
+func TestInlineImageFallback(t *testing.T) {
+	env := map[string]string{"TERM": "xterm-256color"}
+	getenv := func(key string) string { return env[key] }
+
+	sprite, _ := SpriteByName("kitty", getenv)
+	if sprite.Name() != "256" {
+		t.Errorf("expected kitty to fall back to half-blocks, got %s", sprite.Name())
+	}
+
+	env["KITTY_WINDOW_ID"] = "1"
+	sprite, _ = SpriteByName("kitty", getenv)
+	if sprite.Name() != "kitty" {
+		t.Errorf("expected kitty inside kitty, got %s", sprite.Name())
+	}
+
+	lines := sprite.Render(checkerSprite(16), 8, Nearest)
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "\x1b_G") || lines[1] != strings.Repeat(" ", 8) {
+		t.Errorf("expected the image on the first line and blank cells below it, got %q", lines)
+	}
+}
+
+func TestSixelSupported(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected string
+	}{
+		{env: map[string]string{"TERM": "xterm-256color"}, expected: "256"},
+		{env: map[string]string{"TERM": "linux"}, expected: "16"},
+		{env: map[string]string{"TERM": "foot"}, expected: "sixel"},
+		{env: map[string]string{"TERM": "foot-extra"}, expected: "sixel"},
+		{env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, expected: "sixel"},
+		{env: map[string]string{"TERM": "foot", "TMUX": "/tmp/tmux"}, expected: "16"},
+	}
+	for _, c := range cases {
+		sprite, _ := SpriteByName("sixel", func(key string) string { return c.env[key] })
+		if sprite.Name() != c.expected {
+			t.Errorf("%v: Actual: %s - vs - Expected: %s", c.env, sprite.Name(), c.expected)
+		}
+	}
+
+	sprite, _ := SpriteByName("sixel", func(key string) string { return map[string]string{"TERM": "foot"}[key] })
+	lines := sprite.Render(checkerSprite(16), 8, Nearest)
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "\x1bP") {
+		t.Errorf("expected the image alone on one line, got %q", lines)
+	}
+}
@@ -0,0 +1,68 @@
+package card
+
+import (
+	"image"
+	"image/color"
+
+	"go.oneofone.dev/resize"
+)
+
+// Scaler resizes a sprite to the pixels a renderer needs.
+type Scaler struct {
+	Name  string
+	Scale func(img image.Image, width, height int) image.Image
+}
+
+// ScalerNames lists the scalers that can be picked by name. Nearest keeps
+// pixel art crisp, bilinear smooths it and area averages every pixel
+// covered, which keeps thin details when shrinking.
+var ScalerNames = []string{"nearest", "bilinear", "area"}
+
+// Nearest is the default scaler.
+var Nearest = Scaler{Name: "nearest", Scale: resizeWith(resize.NearestNeighbor)}
+
+func ScalerByName(name string) (Scaler, bool) {
+	switch name {
+	case "nearest":
+		return Nearest, true
+	case "bilinear":
+		return Scaler{Name: name, Scale: resizeWith(resize.Bilinear)}, true
+	case "area":
+		return Scaler{Name: name, Scale: areaAverage}, true
+	}
+	return Scaler{}, false
+}
+
+func resizeWith(interp resize.InterpolationFunction) func(image.Image, int, int) image.Image {
+	return func(img image.Image, width, height int) image.Image {
+		return resize.Resize(uint(width), uint(height), img, interp)
+	}
+}
+
+// areaAverage gives every output pixel the average of the source pixels
+// it covers. When enlarging it covers a single pixel, like nearest.
+func areaAverage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+pr, g+pg, b+pb, a+pa
+					n++
+				}
+			}
+			// RGBA is premultiplied, so the channels average directly
+			out.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return out
+}
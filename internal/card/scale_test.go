@@ -0,0 +1,41 @@
+package card
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestAreaAverage(t *testing.T) {
+	// each 2x2 block of the checker is half black and half transparent,
+	// except along the middle where it is all one or the other
+	scaled := areaAverage(checkerSprite(4), 2, 2)
+	if b := scaled.Bounds(); b.Dx() != 2 || b.Dy() != 2 {
+		t.Fatalf("size does not match. Actual: %dx%d - vs - Expected: 2x2", b.Dx(), b.Dy())
+	}
+
+	left := color.RGBA64Model.Convert(scaled.At(0, 0)).(color.RGBA64)
+	right := color.RGBA64Model.Convert(scaled.At(1, 0)).(color.RGBA64)
+	if left.A != 0xffff || right.A != 0 {
+		t.Errorf("alpha does not match. Actual: %d, %d - vs - Expected: %d, %d", left.A, right.A, 0xffff, 0)
+	}
+
+	half := areaAverage(checkerSprite(4), 1, 1)
+	if a := color.RGBAModel.Convert(half.At(0, 0)).(color.RGBA).A; a != 127 {
+		t.Errorf("alpha does not match. Actual: %d - vs - Expected: %d", a, 127)
+	}
+}
+
+func TestScalerByName(t *testing.T) {
+	for _, name := range ScalerNames {
+		scaler, ok := ScalerByName(name)
+		if !ok || scaler.Name != name {
+			t.Fatalf("expected a scaler named %s", name)
+		}
+		if b := scaler.Scale(checkerSprite(8), 4, 2).Bounds(); b.Dx() != 4 || b.Dy() != 2 {
+			t.Errorf("%s: size does not match. Actual: %dx%d - vs - Expected: 4x2", name, b.Dx(), b.Dy())
+		}
+	}
+	if _, ok := ScalerByName("lanczos"); ok {
+		t.Errorf("expected no scaler named lanczos")
+	}
+}
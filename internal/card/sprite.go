@@ -0,0 +1,54 @@
+package card
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// LoadSprite reads a sprite image from path, downloading it from url
+// the first time.
+func LoadSprite(url, path string) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read sprite: %w", err)
+		}
+		if data, err = download(url); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to cache sprite: %w", err)
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+func download(url string) ([]byte, error) {
+	if url == "" {
+		return nil, fmt.Errorf("there is no sprite for this Pokemon")
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sprite: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch sprite: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,221 @@
+package card
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+// SpriteRenderer draws a sprite in a block of text columns wide and
+// about half as many lines tall, as terminal cells are twice as tall as
+// they are wide. The sprite is resized with scale.
+type SpriteRenderer interface {
+	Name() string
+	Render(img image.Image, columns int, scale Scaler) []string
+}
+
+// SpriteNames lists the sprite renderers that can be picked by name.
+var SpriteNames = []string{"truecolor", "256", "16", "grayscale", "braille", "ascii", "kitty", "iterm2", "sixel"}
+
+// SpriteByName returns a sprite renderer by its setting name. The image
+// protocols fall back to the detected half-block renderer when the
+// terminal doesn't look like it supports them.
+func SpriteByName(name string, getenv func(string) string) (SpriteRenderer, bool) {
+	var inline inlineImage
+	switch name {
+	case "kitty":
+		inline = newKitty(DetectSprite(getenv))
+	case "iterm2":
+		inline = newITerm2(DetectSprite(getenv))
+	case "sixel":
+		inline = newSixel(DetectSprite(getenv))
+	default:
+		return plainSpriteByName(name)
+	}
+	if !inline.supported(getenv) {
+		return inline.fallback, true
+	}
+	return inline, true
+}
+
+func plainSpriteByName(name string) (SpriteRenderer, bool) {
+	switch name {
+	case "truecolor":
+		return halfBlock{terminal.TrueColor}, true
+	case "256":
+		return halfBlock{terminal.Color256}, true
+	case "16":
+		return halfBlock{terminal.Color16}, true
+	case "grayscale":
+		return grayBlock{}, true
+	case "braille":
+		return braille{}, true
+	case "ascii":
+		return ascii{}, true
+	}
+	return nil, false
+}
+
+// DetectSprite picks the richest renderer the terminal advertises
+// through COLORTERM and TERM.
+func DetectSprite(getenv func(string) string) SpriteRenderer {
+	depth := terminal.DetectDepth(getenv)
+	if depth == terminal.NoColor {
+		return ascii{}
+	}
+	return halfBlock{depth}
+}
+
+// halfBlock draws two pixels per cell with ▀, the top one in the text
+// color and the bottom one in the background color.
+type halfBlock struct {
+	depth terminal.Depth
+}
+
+func (h halfBlock) Name() string {
+	switch h.depth {
+	case terminal.TrueColor:
+		return "truecolor"
+	case terminal.Color256:
+		return "256"
+	}
+	return "16"
+}
+
+func (h halfBlock) Render(img image.Image, columns int, scale Scaler) []string {
+	return renderHalfBlocks(img, columns, scale, h.depth.Foreground, h.depth.Background)
+}
+
+// grayBlock draws half-blocks in the 24 grays of the xterm-256 palette,
+// by the brightness of each pixel.
+type grayBlock struct{}
+
+func (grayBlock) Name() string {
+	return "grayscale"
+}
+
+func (grayBlock) Render(img image.Image, columns int, scale Scaler) []string {
+	foreground := func(c terminal.RGB) string {
+		return fmt.Sprintf("\x1b[38;5;%dm", grayIndex(c))
+	}
+	background := func(c terminal.RGB) string {
+		return fmt.Sprintf("\x1b[48;5;%dm", grayIndex(c))
+	}
+	return renderHalfBlocks(img, columns, scale, foreground, background)
+}
+
+// grayIndex maps the brightness of c onto the gray ramp, 232 to 255.
+func grayIndex(c terminal.RGB) int {
+	return 232 + (luma(c)*23+127)/255
+}
+
+// luma is the perceived brightness of c, from 0 to 255.
+func luma(c terminal.RGB) int {
+	return (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+}
+
+func renderHalfBlocks(img image.Image, columns int, scale Scaler, foreground, background func(terminal.RGB) string) []string {
+	resized := scale.Scale(img, columns, columns)
+	bounds := resized.Bounds()
+
+	var lines []string
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		var line strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top, topOpaque := toRGB(resized.At(x, y))
+			var bottom terminal.RGB
+			var bottomOpaque bool
+			if y+1 < bounds.Max.Y {
+				bottom, bottomOpaque = toRGB(resized.At(x, y+1))
+			}
+
+			switch {
+			case topOpaque && bottomOpaque:
+				line.WriteString(foreground(top) + background(bottom) + "▀")
+			case topOpaque:
+				line.WriteString(foreground(top) + "\x1b[49m▀")
+			case bottomOpaque:
+				line.WriteString(background(bottom) + "▄")
+			default:
+				line.WriteString(reset + " ")
+			}
+		}
+		line.WriteString(reset)
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// braille draws eight pixels per cell as Braille dots, lit where the
+// sprite is opaque. It needs no color at all.
+type braille struct{}
+
+func (braille) Name() string {
+	return "braille"
+}
+
+// brailleDots are the bits of the dots in a 2x4 Braille cell, by row
+// then column.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+func (braille) Render(img image.Image, columns int, scale Scaler) []string {
+	resized := scale.Scale(img, columns*2, columns*2)
+	bounds := resized.Bounds()
+
+	var lines []string
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 4 {
+		var line strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x += 2 {
+			cell := rune(0x2800)
+			for dy := 0; dy < 4 && y+dy < bounds.Max.Y; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if _, opaque := toRGB(resized.At(x+dx, y+dy)); opaque {
+						cell |= brailleDots[dy][dx]
+					}
+				}
+			}
+			line.WriteRune(cell)
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// ascii shades each cell with a character by its brightness, for
+// terminals and log files that only take plain text.
+type ascii struct{}
+
+func (ascii) Name() string {
+	return "ascii"
+}
+
+// asciiRamp goes from the faintest to the densest character, so darker
+// pixels use denser characters.
+const asciiRamp = ".:-=+*#%@"
+
+func (ascii) Render(img image.Image, columns int, scale Scaler) []string {
+	resized := scale.Scale(img, columns, max(columns/2, 1))
+	bounds := resized.Bounds()
+
+	var lines []string
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		var line strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c, opaque := toRGB(resized.At(x, y))
+			if !opaque {
+				line.WriteByte(' ')
+				continue
+			}
+			line.WriteByte(asciiRamp[(255-luma(c))*(len(asciiRamp)-1)/255])
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
@@ -0,0 +1,95 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+// checkerSprite is opaque black on the left half and transparent on the
+// right half.
+func checkerSprite(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size/2; x++ {
+			img.Set(x, y, color.RGBA{A: 255})
+		}
+	}
+	return img
+}
+
+func TestSpriteRenderersSize(t *testing.T) {
+	for _, name := range []string{"truecolor", "256", "16", "grayscale", "braille", "ascii"} {
+		sprite, ok := SpriteByName(name, func(string) string { return "" })
+		if !ok || sprite.Name() != name {
+			t.Fatalf("expected a sprite renderer named %s", name)
+		}
+
+		lines := sprite.Render(checkerSprite(16), 8, Nearest)
+		if len(lines) != 4 {
+			t.Errorf("%s: len of actual not the same as expected. Actual: %d - vs - Expected: %d", name, len(lines), 4)
+		}
+		for _, line := range lines {
+			if width := terminal.VisibleWidth(line); width != 8 {
+				t.Errorf("%s: width does not match. Actual: %d - vs - Expected: %d", name, width, 8)
+			}
+		}
+	}
+}
+
+func TestPlainSpriteRenderers(t *testing.T) {
+	lines := braille{}.Render(checkerSprite(8), 4, Nearest)
+	if lines[0] != "⣿⣿⠀⠀" {
+		t.Errorf("braille does not match. Actual: %q - vs - Expected: %q", lines[0], "⣿⣿⠀⠀")
+	}
+
+	lines = ascii{}.Render(checkerSprite(8), 4, Nearest)
+	if lines[0] != "@@  " {
+		t.Errorf("ascii does not match. Actual: %q - vs - Expected: %q", lines[0], "@@  ")
+	}
+	for _, line := range append(lines, braille{}.Render(checkerSprite(8), 4, Nearest)...) {
+		if strings.Contains(line, "\x1b") {
+			t.Errorf("expected no escape sequences, got %q", line)
+		}
+	}
+}
+
+func TestGrayscaleSprite(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{A: 255})
+	img.Set(0, 1, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	img.Set(1, 0, color.RGBA{R: 255, A: 255})
+
+	sprite, _ := SpriteByName("grayscale", func(string) string { return "" })
+	lines := sprite.Render(img, 2, Nearest)
+	expected := "\x1b[38;5;232m\x1b[48;5;255m▀\x1b[38;5;239m\x1b[49m▀" + reset
+	if len(lines) != 1 || lines[0] != expected {
+		t.Errorf("grayscale does not match. Actual: %q - vs - Expected: %q", lines, expected)
+	}
+}
+
+func TestDetectSprite(t *testing.T) {
+	cases := []struct {
+		colorterm string
+		term      string
+		expected  string
+	}{
+		{colorterm: "truecolor", term: "xterm-256color", expected: "truecolor"},
+		{colorterm: "24bit", expected: "truecolor"},
+		{term: "screen-256color", expected: "256"},
+		{term: "xterm", expected: "16"},
+		{term: "dumb", expected: "ascii"},
+		{expected: "16"},
+	}
+
+	for _, c := range cases {
+		env := map[string]string{"COLORTERM": c.colorterm, "TERM": c.term}
+		actual := DetectSprite(func(key string) string { return env[key] }).Name()
+		if actual != c.expected {
+			t.Errorf("COLORTERM=%q TERM=%q: Actual: %s - vs - Expected: %s", c.colorterm, c.term, actual, c.expected)
+		}
+	}
+}
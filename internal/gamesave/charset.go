@@ -0,0 +1,64 @@
+package gamesave
+
+import "strings"
+
+// textTerminator ends names in the Gen 1 and Gen 2 character set.
+const textTerminator = 0x50
+
+// charset maps the printable characters of the English Gen 1 and Gen 2
+// games. Letters and digits are filled in by init.
+var charset = map[byte]string{
+	0x7F: " ",
+	0x9A: "(", 0x9B: ")", 0x9C: ":", 0x9D: ";", 0x9E: "[", 0x9F: "]",
+	0xBA: "é", 0xBB: "'d", 0xBC: "'l", 0xBD: "'s", 0xBE: "'t", 0xBF: "'v",
+	0xE0: "'", 0xE1: "PK", 0xE2: "MN", 0xE3: "-", 0xE4: "'r", 0xE5: "'m",
+	0xE6: "?", 0xE7: "!", 0xE8: ".",
+	0xEF: "♂", 0xF0: "¥", 0xF1: "×", 0xF3: "/", 0xF4: ",", 0xF5: "♀",
+}
+
+func init() {
+	for i := byte(0); i < 26; i++ {
+		charset[0x80+i] = string(rune('A' + i))
+		charset[0xA0+i] = string(rune('a' + i))
+	}
+	for i := byte(0); i < 10; i++ {
+		charset[0xF6+i] = string(rune('0' + i))
+	}
+}
+
+// decodeText reads a terminated name. Bytes outside the character set,
+// such as the glitch characters, are shown as ?.
+func decodeText(data []byte) string {
+	var b strings.Builder
+	for _, c := range data {
+		if c == textTerminator {
+			break
+		}
+		if s, ok := charset[c]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteString("?")
+		}
+	}
+	return b.String()
+}
+
+// IsDefaultName reports whether a nickname is just the species name the
+// games fill in, such as "NIDORAN♂" for "nidoran-m".
+func IsDefaultName(nickname, species string) bool {
+	normalize := func(s string) string {
+		var b strings.Builder
+		for _, r := range strings.ToLower(s) {
+			switch {
+			case r == '♂':
+				b.WriteRune('m')
+			case r == '♀':
+				b.WriteRune('f')
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}
+	return normalize(nickname) == normalize(species)
+}
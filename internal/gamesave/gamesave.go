@@ -0,0 +1,332 @@
+// Package gamesave reads the Pokemon stored in Gen 1 (Red, Blue, Yellow)
+// and Gen 2 (Gold, Silver, Crystal) battery saves.
+package gamesave
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// saveSize is the size of the cartridge RAM. Emulators may append clock
+// data, so longer files are accepted.
+const saveSize = 0x8000
+
+const (
+	nameLength = 11
+	eggSpecies = 0xFD
+	listEnd    = 0xFF
+)
+
+type Game string
+
+const (
+	Gen1       Game = "Red/Blue/Yellow"
+	GoldSilver Game = "Gold/Silver"
+	Crystal    Game = "Crystal"
+)
+
+// DVs are the Gen 1 and Gen 2 individual values, from 0 to 15. Special
+// covers both special attack and special defense.
+type DVs struct {
+	Attack  int
+	Defense int
+	Speed   int
+	Special int
+}
+
+// HP is derived from the lowest bit of the other DVs.
+func (d DVs) HP() int {
+	return (d.Attack&1)<<3 | (d.Defense&1)<<2 | (d.Speed&1)<<1 | d.Special&1
+}
+
+// Shiny reports whether the DVs make a Pokemon shiny in Gen 2.
+func (d DVs) Shiny() bool {
+	return d.Defense == 10 && d.Speed == 10 && d.Special == 10 && d.Attack&2 == 2
+}
+
+func decodeDVs(data []byte) DVs {
+	return DVs{
+		Attack:  int(data[0] >> 4),
+		Defense: int(data[0] & 0x0F),
+		Speed:   int(data[1] >> 4),
+		Special: int(data[1] & 0x0F),
+	}
+}
+
+// Mon is a Pokemon from a save. Species is the national Pokedex number
+// and Moves are move IDs, both as used by pokeapi.
+type Mon struct {
+	Species  int
+	Nickname string
+	Level    int
+	OT       string
+	OTID     int
+	Moves    [4]int
+	DVs      DVs
+	// Box is 0 for the party, otherwise the PC box number from 1.
+	Box int
+}
+
+type Save struct {
+	Game      Game
+	Trainer   string
+	TrainerID int
+	Party     []Mon
+	Boxes     []Mon
+	// Warnings lists boxes that were skipped because their data is
+	// unused or damaged.
+	Warnings []string
+}
+
+// Mons returns the party followed by the boxes.
+func (s Save) Mons() []Mon {
+	return append(append([]Mon{}, s.Party...), s.Boxes...)
+}
+
+// layout holds where a game keeps its data and how its Pokemon are
+// stored.
+type layout struct {
+	game        Game
+	trainer     int
+	trainerID   int
+	party       int
+	currentBox  int
+	boxNumber   int
+	boxBanks    []int
+	boxesInBank int
+	boxStride   int
+	partyMon    int
+	boxMon      int
+	boxCapacity int
+	checksum    func(data []byte) bool
+	species     func(index byte) (int, bool)
+	level       func(mon []byte, party bool) int
+	fields      monFields
+}
+
+// monFields are the offsets of the fields Mon reads from a Pokemon's
+// data.
+type monFields struct {
+	moves int
+	otID  int
+	dvs   int
+}
+
+var layouts = []layout{
+	{
+		game:        GoldSilver,
+		trainer:     0x200B,
+		trainerID:   0x2009,
+		party:       0x288A,
+		currentBox:  0x2D6C,
+		boxNumber:   0x2724,
+		boxBanks:    []int{0x4000, 0x6000},
+		boxesInBank: 7,
+		boxStride:   0x450,
+		partyMon:    48,
+		boxMon:      32,
+		boxCapacity: 20,
+		checksum: func(data []byte) bool {
+			return binary.LittleEndian.Uint16(data[0x2D69:]) == checksum16(data[0x2009:0x2D69])
+		},
+		species: gen2Species,
+		level: func(mon []byte, party bool) int {
+			return int(mon[0x1F])
+		},
+		fields: monFields{moves: 0x02, otID: 0x06, dvs: 0x15},
+	},
+	{
+		game:        Crystal,
+		trainer:     0x200B,
+		trainerID:   0x2009,
+		party:       0x2865,
+		currentBox:  0x2D10,
+		boxNumber:   0x2700,
+		boxBanks:    []int{0x4000, 0x6000},
+		boxesInBank: 7,
+		boxStride:   0x450,
+		partyMon:    48,
+		boxMon:      32,
+		boxCapacity: 20,
+		checksum: func(data []byte) bool {
+			return binary.LittleEndian.Uint16(data[0x2D0D:]) == checksum16(data[0x2009:0x2B83])
+		},
+		species: gen2Species,
+		level: func(mon []byte, party bool) int {
+			return int(mon[0x1F])
+		},
+		fields: monFields{moves: 0x02, otID: 0x06, dvs: 0x15},
+	},
+	{
+		game:        Gen1,
+		trainer:     0x2598,
+		trainerID:   0x2605,
+		party:       0x2F2C,
+		currentBox:  0x30C0,
+		boxNumber:   0x284C,
+		boxBanks:    []int{0x4000, 0x6000},
+		boxesInBank: 6,
+		boxStride:   0x462,
+		partyMon:    44,
+		boxMon:      33,
+		boxCapacity: 20,
+		checksum: func(data []byte) bool {
+			return data[0x3523] == checksum8(data[0x2598:0x3523])
+		},
+		species: func(index byte) (int, bool) {
+			id, ok := gen1Species[index]
+			return id, ok
+		},
+		level: func(mon []byte, party bool) int {
+			if party {
+				return int(mon[0x21])
+			}
+			return int(mon[0x03])
+		},
+		fields: monFields{moves: 0x08, otID: 0x0C, dvs: 0x1B},
+	},
+}
+
+func gen2Species(index byte) (int, bool) {
+	return int(index), index >= 1 && index <= 251
+}
+
+// checksum8 is the Gen 1 checksum: the inverted sum of the bytes.
+func checksum8(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return ^sum
+}
+
+// checksum16 is the Gen 2 checksum: the sum of the bytes.
+func checksum16(data []byte) uint16 {
+	var sum uint16
+	for _, b := range data {
+		sum += uint16(b)
+	}
+	return sum
+}
+
+var ErrUnknownSave = errors.New("not a Red/Blue/Yellow or Gold/Silver/Crystal save, or its checksum is wrong")
+
+// Parse reads a save, telling the games apart by which checksum matches.
+// The 16-bit Gen 2 checksums are tried first, as the 8-bit Gen 1 one is
+// more likely to match by chance.
+func Parse(data []byte) (Save, error) {
+	if len(data) < saveSize {
+		return Save{}, fmt.Errorf("save is %d bytes, expected at least %d", len(data), saveSize)
+	}
+
+	for _, l := range layouts {
+		if l.checksum(data) {
+			return l.parse(data)
+		}
+	}
+	return Save{}, ErrUnknownSave
+}
+
+func (l layout) parse(data []byte) (Save, error) {
+	save := Save{
+		Game:      l.game,
+		Trainer:   decodeText(data[l.trainer : l.trainer+nameLength]),
+		TrainerID: int(binary.BigEndian.Uint16(data[l.trainerID:])),
+	}
+
+	party, err := l.readList(data[l.party:], 6, l.partyMon, 0)
+	if err != nil {
+		return Save{}, fmt.Errorf("failed to read the party: %w", err)
+	}
+	save.Party = party
+
+	// the open box lives in the main data, its copy in the box banks is
+	// only updated when switching boxes
+	current := int(data[l.boxNumber]&0x7F) + 1
+	for box := 1; box <= len(l.boxBanks)*l.boxesInBank; box++ {
+		var list []byte
+		if box == current {
+			list = data[l.currentBox:]
+		} else {
+			bank := l.boxBanks[(box-1)/l.boxesInBank]
+			list = data[bank+(box-1)%l.boxesInBank*l.boxStride:]
+			if l.game == Gen1 {
+				formatted, valid := gen1BoxChecksum(data, bank, (box-1)%l.boxesInBank)
+				if !formatted {
+					// the bank is set up the first time you switch boxes
+					continue
+				}
+				if !valid {
+					save.Warnings = append(save.Warnings, fmt.Sprintf("skipped box %d: its checksum is wrong", box))
+					continue
+				}
+			}
+		}
+
+		mons, err := l.readList(list, l.boxCapacity, l.boxMon, box)
+		if err != nil {
+			save.Warnings = append(save.Warnings, fmt.Sprintf("skipped box %d: %v", box, err))
+			continue
+		}
+		save.Boxes = append(save.Boxes, mons...)
+	}
+	return save, nil
+}
+
+// gen1BoxChecksum checks one box of a Gen 1 box bank. Each bank ends with
+// a checksum of the whole bank, which only matches once the game has set
+// the bank up, followed by one checksum per box.
+func gen1BoxChecksum(data []byte, bank, index int) (formatted, valid bool) {
+	const stride = 0x462
+	sums := bank + 6*stride
+	if data[sums] != checksum8(data[bank:sums]) {
+		return false, false
+	}
+	start := bank + index*stride
+	return true, data[sums+1+index] == checksum8(data[start:start+stride])
+}
+
+// readList reads a Pokemon list: a count, the species list, the
+// Pokemon data, then the OT names and the nicknames.
+func (l layout) readList(list []byte, capacity, monSize, box int) ([]Mon, error) {
+	count := int(list[0])
+	if count > capacity {
+		return nil, fmt.Errorf("holds %d Pokemon, at most %d fit", count, capacity)
+	}
+	if list[1+count] != listEnd {
+		return nil, errors.New("species list is not terminated")
+	}
+
+	monStart := 1 + capacity + 1
+	otStart := monStart + capacity*monSize
+	nickStart := otStart + capacity*nameLength
+
+	mons := []Mon{}
+	for i := 0; i < count; i++ {
+		if list[1+i] == eggSpecies {
+			continue
+		}
+
+		mon := list[monStart+i*monSize : monStart+(i+1)*monSize]
+		species, ok := l.species(mon[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown species index 0x%02X", mon[0])
+		}
+
+		m := Mon{
+			Species:  species,
+			Nickname: decodeText(list[nickStart+i*nameLength : nickStart+(i+1)*nameLength]),
+			Level:    l.level(mon, box == 0),
+			OT:       decodeText(list[otStart+i*nameLength : otStart+(i+1)*nameLength]),
+			OTID:     int(binary.BigEndian.Uint16(mon[l.fields.otID:])),
+			DVs:      decodeDVs(mon[l.fields.dvs:]),
+			Box:      box,
+		}
+		for j := range m.Moves {
+			m.Moves[j] = int(mon[l.fields.moves+j])
+		}
+		mons = append(mons, m)
+	}
+	return mons, nil
+}
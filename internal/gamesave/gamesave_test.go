@@ -0,0 +1,305 @@
+package gamesave
+
+import (
+	"encoding/binary"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate the fixture saves")
+
+func encodeText(s string, n int) []byte {
+	reverse := make(map[string]byte, len(charset))
+	for b, c := range charset {
+		reverse[c] = b
+	}
+
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = textTerminator
+	}
+	i := 0
+	for _, r := range s {
+		out[i] = reverse[string(r)]
+		i++
+	}
+	return out
+}
+
+func gen1Index(species int) byte {
+	for index, id := range gen1Species {
+		if id == species {
+			return index
+		}
+	}
+	panic("unknown species")
+}
+
+// writeList lays out a Pokemon list the way readList expects it.
+func writeList(data []byte, l layout, capacity, monSize int, mons []Mon, eggs int) {
+	data[0] = byte(len(mons) + eggs)
+	monStart := 1 + capacity + 1
+	otStart := monStart + capacity*monSize
+	nickStart := otStart + capacity*nameLength
+
+	for i, m := range mons {
+		mon := data[monStart+i*monSize : monStart+(i+1)*monSize]
+		data[1+i] = byte(m.Species)
+		mon[0] = byte(m.Species)
+		if l.game == Gen1 {
+			data[1+i] = gen1Index(m.Species)
+			mon[0] = data[1+i]
+			mon[0x03] = byte(m.Level)
+			if m.Box == 0 {
+				mon[0x21] = byte(m.Level)
+			}
+		} else {
+			mon[0x1F] = byte(m.Level)
+		}
+		for j, move := range m.Moves {
+			mon[l.fields.moves+j] = byte(move)
+		}
+		binary.BigEndian.PutUint16(mon[l.fields.otID:], uint16(m.OTID))
+		mon[l.fields.dvs] = byte(m.DVs.Attack<<4 | m.DVs.Defense)
+		mon[l.fields.dvs+1] = byte(m.DVs.Speed<<4 | m.DVs.Special)
+		copy(data[otStart+i*nameLength:], encodeText(m.OT, nameLength))
+		copy(data[nickStart+i*nameLength:], encodeText(m.Nickname, nameLength))
+	}
+	for i := len(mons); i < len(mons)+eggs; i++ {
+		data[1+i] = eggSpecies
+	}
+	data[1+len(mons)+eggs] = listEnd
+}
+
+func layoutOf(game Game) layout {
+	for _, l := range layouts {
+		if l.game == game {
+			return l
+		}
+	}
+	panic("unknown game")
+}
+
+// buildSave writes a save holding the party, the open box and one other
+// box, with valid checksums.
+func buildSave(expected Save, currentBox, otherBox int, eggs int) []byte {
+	l := layoutOf(expected.Game)
+	data := make([]byte, saveSize)
+
+	copy(data[l.trainer:], encodeText(expected.Trainer, nameLength))
+	binary.BigEndian.PutUint16(data[l.trainerID:], uint16(expected.TrainerID))
+	writeList(data[l.party:], l, 6, l.partyMon, expected.Party, eggs)
+	data[l.boxNumber] = byte(currentBox - 1)
+
+	var current, other []Mon
+	for _, m := range expected.Boxes {
+		if m.Box == currentBox {
+			current = append(current, m)
+		} else {
+			other = append(other, m)
+		}
+	}
+	writeList(data[l.currentBox:], l, l.boxCapacity, l.boxMon, current, 0)
+
+	// the games set up every box as an empty list, for Gen 1 only in the
+	// banks that have been used
+	bank := l.boxBanks[(otherBox-1)/l.boxesInBank]
+	for box := 1; box <= len(l.boxBanks)*l.boxesInBank; box++ {
+		boxBank := l.boxBanks[(box-1)/l.boxesInBank]
+		if l.game == Gen1 && boxBank != bank {
+			continue
+		}
+		start := boxBank + (box-1)%l.boxesInBank*l.boxStride
+		if box == otherBox {
+			writeList(data[start:], l, l.boxCapacity, l.boxMon, other, 0)
+		} else {
+			data[start+1] = listEnd
+		}
+	}
+
+	if l.game == Gen1 {
+		sums := bank + 6*l.boxStride
+		for i := 0; i < 6; i++ {
+			start := bank + i*l.boxStride
+			data[sums+1+i] = checksum8(data[start : start+l.boxStride])
+		}
+		data[sums] = checksum8(data[bank:sums])
+		data[0x3523] = checksum8(data[0x2598:0x3523])
+		return data
+	}
+
+	if l.game == Crystal {
+		binary.LittleEndian.PutUint16(data[0x2D0D:], checksum16(data[0x2009:0x2B83]))
+	} else {
+		binary.LittleEndian.PutUint16(data[0x2D69:], checksum16(data[0x2009:0x2D69]))
+	}
+	return data
+}
+
+var fixtures = []struct {
+	file       string
+	save       Save
+	currentBox int
+	otherBox   int
+	eggs       int
+}{
+	{
+		file: "red.sav",
+		save: Save{
+			Game:      Gen1,
+			Trainer:   "RED",
+			TrainerID: 12345,
+			Party: []Mon{
+				{Species: 25, Nickname: "SPARKY", Level: 25, OT: "RED", OTID: 12345, Moves: [4]int{84, 45}, DVs: DVs{Attack: 9, Defense: 8, Speed: 15, Special: 3}},
+				{Species: 4, Nickname: "CHARMANDER", Level: 12, OT: "RED", OTID: 12345, Moves: [4]int{10, 45}, DVs: DVs{Attack: 1, Defense: 2, Speed: 3, Special: 4}},
+			},
+			Boxes: []Mon{
+				{Species: 19, Nickname: "RATTATA", Level: 3, OT: "RED", OTID: 12345, Moves: [4]int{33}, Box: 1},
+				{Species: 32, Nickname: "NIDORAN♂", Level: 10, OT: "BLUE", OTID: 54321, Moves: [4]int{43, 33}, Box: 8},
+			},
+		},
+		currentBox: 1,
+		otherBox:   8,
+	},
+	{
+		file: "crystal.sav",
+		save: Save{
+			Game:      Crystal,
+			Trainer:   "KRIS",
+			TrainerID: 4242,
+			Party: []Mon{
+				{Species: 155, Nickname: "BLAZE", Level: 14, OT: "KRIS", OTID: 4242, Moves: [4]int{33, 43, 52}, DVs: DVs{Attack: 10, Defense: 10, Speed: 10, Special: 10}},
+			},
+			Boxes: []Mon{
+				{Species: 175, Nickname: "TOGEPI", Level: 5, OT: "KRIS", OTID: 4242, Moves: [4]int{45, 204}, Box: 3},
+				{Species: 249, Nickname: "LUGIA", Level: 40, OT: "KRIS", OTID: 4242, Moves: [4]int{177}, Box: 12},
+			},
+		},
+		currentBox: 3,
+		otherBox:   12,
+		eggs:       1,
+	},
+	{
+		file: "gold.sav",
+		save: Save{
+			Game:      GoldSilver,
+			Trainer:   "GOLD",
+			TrainerID: 1,
+			Party: []Mon{
+				{Species: 152, Nickname: "CHIKORITA", Level: 5, OT: "GOLD", OTID: 1, Moves: [4]int{33, 45}},
+			},
+			Boxes: []Mon{
+				{Species: 161, Nickname: "SENTRET", Level: 2, OT: "GOLD", OTID: 1, Moves: [4]int{33}, Box: 2},
+			},
+		},
+		currentBox: 1,
+		otherBox:   2,
+	},
+}
+
+func TestParseFixtures(t *testing.T) {
+	for _, f := range fixtures {
+		path := filepath.Join("testdata", f.file)
+		if *update {
+			data := buildSave(f.save, f.currentBox, f.otherBox, f.eggs)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := Parse(data)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", f.file, err)
+		}
+
+		if actual.Game != f.save.Game || actual.Trainer != f.save.Trainer || actual.TrainerID != f.save.TrainerID {
+			t.Errorf("%s: trainer does not match. Actual: %s %s %d - vs - Expected: %s %s %d", f.file,
+				actual.Game, actual.Trainer, actual.TrainerID, f.save.Game, f.save.Trainer, f.save.TrainerID)
+		}
+		if len(actual.Warnings) != 0 {
+			t.Errorf("%s: unexpected warnings: %v", f.file, actual.Warnings)
+		}
+
+		expected := f.save.Mons()
+		mons := actual.Mons()
+		if len(mons) != len(expected) {
+			t.Fatalf("%s: len of actual not the same as expected. Actual: %d - vs - Expected: %d", f.file, len(mons), len(expected))
+		}
+		for i := range mons {
+			if mons[i] != expected[i] {
+				t.Errorf("%s: mon does not match. Actual: %+v - vs - Expected: %+v", f.file, mons[i], expected[i])
+			}
+		}
+	}
+}
+
+func TestParseRejectsBadChecksum(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "gold.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[0x2D69]++
+
+	if _, err := Parse(data); !errors.Is(err, ErrUnknownSave) {
+		t.Errorf("expected an unknown save error, got %v", err)
+	}
+	if _, err := Parse(data[:100]); err == nil {
+		t.Errorf("expected an error for a truncated save")
+	}
+}
+
+func TestParseSkipsDamagedBox(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "red.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// damage box 8, then fix the bank checksum so only the box is wrong
+	bank := 0x6000
+	data[bank+0x462+0x30]++
+	data[bank+6*0x462] = checksum8(data[bank : bank+6*0x462])
+
+	save, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(save.Warnings) != 1 || len(save.Boxes) != 1 {
+		t.Errorf("expected box 8 to be skipped with a warning, got %v %v", save.Boxes, save.Warnings)
+	}
+}
+
+func TestDVs(t *testing.T) {
+	shiny := DVs{Attack: 10, Defense: 10, Speed: 10, Special: 10}
+	if !shiny.Shiny() || shiny.HP() != 0 {
+		t.Errorf("expected shiny DVs with 0 HP, got %v %d", shiny.Shiny(), shiny.HP())
+	}
+	plain := DVs{Attack: 15, Defense: 15, Speed: 15, Special: 15}
+	if plain.Shiny() || plain.HP() != 15 {
+		t.Errorf("expected plain DVs with 15 HP, got %v %d", plain.Shiny(), plain.HP())
+	}
+}
+
+func TestIsDefaultName(t *testing.T) {
+	cases := []struct {
+		nickname string
+		species  string
+		expected bool
+	}{
+		{nickname: "NIDORAN♂", species: "nidoran-m", expected: true},
+		{nickname: "MR.MIME", species: "mr-mime", expected: true},
+		{nickname: "PIKACHU", species: "pikachu", expected: true},
+		{nickname: "SPARKY", species: "pikachu", expected: false},
+	}
+
+	for _, c := range cases {
+		if actual := IsDefaultName(c.nickname, c.species); actual != c.expected {
+			t.Errorf("%s as %s: Actual: %v - vs - Expected: %v", c.nickname, c.species, actual, c.expected)
+		}
+	}
+}
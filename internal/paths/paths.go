@@ -0,0 +1,197 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+const (
+	appName      = "pokedex-cli"
+	LegacyDir    = ".cache"
+	DataDirEnv   = "POKEDEX_DATA_DIR"
+	CacheDirEnv  = "POKEDEX_CACHE_DIR"
+	legacyCaught = "caught.json"
+)
+
+// Dirs holds where the Pokedex keeps its files. Data holds the caught
+// Pokemon, Cache holds anything that can be rebuilt such as rendered
+// sprites.
+type Dirs struct {
+	Data  string
+	Cache string
+}
+
+// Resolve picks the data and cache directories. Explicit values (from
+// flags) win over the POKEDEX_* environment variables, which win over
+// the XDG base directories.
+func Resolve(dataDir, cacheDir string) (Dirs, error) {
+	var err error
+	if dataDir == "" {
+		dataDir = os.Getenv(DataDirEnv)
+	}
+	if dataDir == "" {
+		dataDir, err = xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+		if err != nil {
+			return Dirs{}, err
+		}
+	}
+
+	if cacheDir == "" {
+		cacheDir = os.Getenv(CacheDirEnv)
+	}
+	if cacheDir == "" {
+		cacheDir, err = xdgDir("XDG_CACHE_HOME", ".cache")
+		if err != nil {
+			return Dirs{}, err
+		}
+	}
+
+	return Dirs{Data: dataDir, Cache: cacheDir}, nil
+}
+
+func xdgDir(env, fallback string) (string, error) {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, appName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, fallback, appName), nil
+}
+
+// MigrateLegacy moves files from the old cwd-relative .cache directory
+// into dirs: caught.json goes to the data directory and the rendered
+// cards of the species in it to the cache directory. Nothing is touched
+// unless the directory holds a caught.json, or when it is the cache root
+// other programs share, as it is when run from the home directory. Files
+// already present in dirs are never overwritten. It returns how many
+// files were moved.
+func MigrateLegacy(legacyDir string, dirs Dirs) (int, error) {
+	if isCacheRoot(legacyDir) {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(legacyDir, legacyCaught))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read %s: %w", legacyCaught, err)
+	}
+	species, err := pokedex.SaveSpecies(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", legacyCaught, err)
+	}
+
+	type move struct {
+		name string
+		dest string
+	}
+	// caught.json goes last so an interrupted migration is picked up
+	// again on the next start
+	var moves []move
+	for _, name := range species {
+		if name == "" || filepath.Base(name) != name || name == ".." {
+			continue
+		}
+		moves = append(moves, move{name + ".txt", filepath.Join(dirs.Cache, name+".txt")})
+	}
+	moves = append(moves, move{legacyCaught, filepath.Join(dirs.Data, legacyCaught)})
+
+	moved := 0
+	for _, m := range moves {
+		src := filepath.Join(legacyDir, m.name)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if _, err := os.Stat(m.dest); err == nil {
+			continue
+		}
+
+		if err := moveFile(src, m.dest); err != nil {
+			return moved, fmt.Errorf("failed to migrate %s: %w", m.name, err)
+		}
+		moved++
+	}
+
+	// only removes the directory once it is empty
+	_ = os.Remove(legacyDir)
+
+	return moved, nil
+}
+
+// isCacheRoot reports whether dir is the XDG cache directory itself,
+// or ~/.cache, rather than one belonging to the Pokedex.
+func isCacheRoot(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	var roots []string
+	if base := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(base) {
+		roots = append(roots, base)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		roots = append(roots, filepath.Join(home, ".cache"))
+	}
+
+	for _, root := range roots {
+		if sameDir(abs, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameDir(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ai, errA := os.Stat(a)
+	bi, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(ai, bi)
+}
+
+func moveFile(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	err := os.Rename(src, dest)
+	if err == nil {
+		return nil
+	}
+
+	var linkErr *os.LinkError
+	if !errors.As(err, &linkErr) {
+		return err
+	}
+
+	// rename fails across filesystems, fall back to copying
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
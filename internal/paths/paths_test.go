@@ -0,0 +1,142 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("HOME", "/home/ash")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv(DataDirEnv, "")
+	t.Setenv(CacheDirEnv, "")
+
+	cases := []struct {
+		name     string
+		env      map[string]string
+		dataFlag string
+		expected Dirs
+	}{
+		{
+			name: "home fallback",
+			expected: Dirs{
+				Data:  "/home/ash/.local/share/pokedex-cli",
+				Cache: "/home/ash/.cache/pokedex-cli",
+			},
+		},
+		{
+			name: "xdg",
+			env:  map[string]string{"XDG_DATA_HOME": "/xdg/data", "XDG_CACHE_HOME": "/xdg/cache"},
+			expected: Dirs{
+				Data:  "/xdg/data/pokedex-cli",
+				Cache: "/xdg/cache/pokedex-cli",
+			},
+		},
+		{
+			name: "relative xdg is ignored",
+			env:  map[string]string{"XDG_DATA_HOME": "relative"},
+			expected: Dirs{
+				Data:  "/home/ash/.local/share/pokedex-cli",
+				Cache: "/home/ash/.cache/pokedex-cli",
+			},
+		},
+		{
+			name:     "env and flag override",
+			env:      map[string]string{DataDirEnv: "/env/data", CacheDirEnv: "/env/cache"},
+			dataFlag: "/flag/data",
+			expected: Dirs{
+				Data:  "/flag/data",
+				Cache: "/env/cache",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+			actual, err := Resolve(c.dataFlag, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf("dirs do not match. Actual: %v - vs - Expected: %v", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, LegacyDir)
+	dirs := Dirs{Data: filepath.Join(root, "data"), Cache: filepath.Join(root, "cache")}
+
+	if err := os.MkdirAll(legacy, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"caught.json": `{"25": "pikachu"}`,
+		"pikachu.txt": "sprite",
+		"notes.txt":   "another program's",
+		"notes.md":    "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(legacy, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := MigrateLegacy(legacy, dirs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("expected 2 files moved, got %d", moved)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dirs.Data, "caught.json")); err != nil || string(data) != files["caught.json"] {
+		t.Errorf("expected caught.json in data dir, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dirs.Cache, "pikachu.txt")); err != nil {
+		t.Errorf("expected sprite in cache dir: %v", err)
+	}
+	for _, name := range []string{"notes.md", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(legacy, name)); err != nil {
+			t.Errorf("expected unrelated files to stay: %v", err)
+		}
+	}
+
+	moved, err = MigrateLegacy(filepath.Join(root, "missing"), dirs)
+	if err != nil || moved != 0 {
+		t.Errorf("expected missing legacy dir to be a no-op, got %d (%v)", moved, err)
+	}
+
+	// a .cache without a Pokedex save belongs to someone else
+	if err := os.WriteFile(filepath.Join(legacy, "bulbasaur.txt"), []byte("sprite"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := MigrateLegacy(legacy, dirs); err != nil || moved != 0 {
+		t.Errorf("expected nothing moved without caught.json, got %d (%v)", moved, err)
+	}
+}
+
+func TestMigrateLegacySkipsCacheRoot(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, LegacyDir)
+	t.Setenv("XDG_CACHE_HOME", legacy)
+	dirs := Dirs{Data: filepath.Join(root, "data"), Cache: filepath.Join(legacy, appName)}
+
+	if err := os.MkdirAll(legacy, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "caught.json"), []byte(`{"25": "pikachu"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if moved, err := MigrateLegacy(legacy, dirs); err != nil || moved != 0 {
+		t.Errorf("expected the cache root to be left alone, got %d (%v)", moved, err)
+	}
+}
@@ -2,9 +2,15 @@ package pokeapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fotis-sofoulis/pokedex-cli/internal/pokecache"
 )
@@ -33,156 +39,354 @@ type PokemonLocationEncounter struct {
 }
 
 type PokemonEncounter struct {
-	Pokemon Pokemon `json:"pokemon"`
+	Pokemon        Pokemon `json:"pokemon"`
+	VersionDetails []struct {
+		EncounterDetails []struct {
+			MinLevel int `json:"min_level"`
+			MaxLevel int `json:"max_level"`
+		} `json:"encounter_details"`
+	} `json:"version_details"`
+}
+
+// LevelRange returns the lowest and highest level the Pokemon can be
+// encountered at in the area, across all game versions.
+func (e PokemonEncounter) LevelRange() (int, int) {
+	minLevel, maxLevel := 0, 0
+	for _, v := range e.VersionDetails {
+		for _, d := range v.EncounterDetails {
+			if minLevel == 0 || d.MinLevel < minLevel {
+				minLevel = d.MinLevel
+			}
+			if d.MaxLevel > maxLevel {
+				maxLevel = d.MaxLevel
+			}
+		}
+	}
+	return minLevel, maxLevel
 }
 
 type Pokemon struct {
 	ID			   int    `json:"id"`
 	Name		   string `json:"name"`
+	URL            string `json:"url"`
 	BaseExperience int    `json:"base_experience"`
 }
 
-var cache *pokecache.Cache
+// IDFromURL returns the trailing numeric ID of a resource url such as
+// https://pokeapi.co/api/v2/pokemon/25/, or 0 if it has none.
+func IDFromURL(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+const baseURL = "https://pokeapi.co/api/v2/"
+
+type pokemonEntry struct {
+	pokemon Pokemon
+	raw     []byte
+}
+
+// ErrPokemonNotFound is returned by GetPokemon when pokeapi has no
+// Pokemon by that name.
+var ErrPokemonNotFound = errors.New("pokemon not found")
+
+var (
+	disk         *pokecache.Disk
+	cache        *pokecache.BytesCache
+	areaCache    *pokecache.Cache[string, LocationAreaDetailsResp]
+	pokemonCache *pokecache.Cache[string, pokemonEntry]
+)
 
-func InitCache(c *pokecache.Cache) {
+// InitCache sets the cache for raw response bodies and creates the typed
+// caches for decoded values, which expire on the same interval.
+func InitCache(c *pokecache.BytesCache) {
 	cache = c
+	areaCache = pokecache.New[string, LocationAreaDetailsResp](c.Interval(), nil)
+	pokemonCache = pokecache.New[string](c.Interval(), func(e pokemonEntry) int {
+		return len(e.raw)
+	})
 }
 
-func FetchLocationAreas(url string) (LocationAreaResp, error) {
-	if url == "" {
-		url = "https://pokeapi.co/api/v2/location-area/"
-	}
+// SetCacheDir keeps responses in dir as well, so they can be reused or
+// revalidated after a restart.
+func SetCacheDir(dir string) {
+	disk = pokecache.NewDisk(dir)
+}
+
+func Cache() *pokecache.BytesCache {
+	return cache
+}
 
-	// fetch from cache
-	if data, exist := cache.Get(url); exist {
-		var res LocationAreaResp
-		if err := json.Unmarshal(data, &res); err == nil {
-			return res, nil
+// CacheStats returns the stats of the response cache, with the hits
+// served by the decoded caches added, as those never reach it.
+func CacheStats() pokecache.Stats {
+	stats := cache.Stats()
+	stats.Hits += areaCache.Stats().Hits + pokemonCache.Stats().Hits
+	return stats
+}
+
+// ListCache lists the cached responses and decoded values whose url
+// matches, oldest first. A url cached both ways is listed once.
+func ListCache(match func(string) bool) []pokecache.EntryInfo[string] {
+	entries := cache.List(match)
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		seen[e.Key] = true
+	}
+	for _, decoded := range [][]pokecache.EntryInfo[string]{areaCache.List(match), pokemonCache.List(match)} {
+		for _, e := range decoded {
+			if !seen[e.Key] {
+				seen[e.Key] = true
+				entries = append(entries, e)
+			}
 		}
 	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Age > entries[j].Age
+	})
+	return entries
+}
 
-	res, err := http.Get(url)
-	if err != nil {
-		return LocationAreaResp{}, fmt.Errorf("failed to fetch location areas: %w", err)
-	}
-	defer res.Body.Close()
+// ClearCache removes every cached response and decoded value whose url
+// starts with prefix, in memory and on disk.
+func ClearCache(prefix string) int {
+	return clearCache(pokecache.HasPrefix(prefix))
+}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return LocationAreaResp{}, fmt.Errorf("failed to read response body: %w", err)
-	}
+// ForgetURL removes the cached response and decoded value of exactly url.
+func ForgetURL(url string) int {
+	return clearCache(pokecache.Is(url))
+}
 
-	cache.Add(url, body)
+func clearCache(match func(string) bool) int {
+	areaCache.Clear(match)
+	pokemonCache.Clear(match)
+	// a response is usually both in memory and on disk, count it once
+	return max(cache.Clear(match), disk.Clear(match))
+}
 
-	var data LocationAreaResp
-	if err := json.Unmarshal(body, &data); err != nil {
-		return LocationAreaResp{}, fmt.Errorf("failed to parse JSON: %w", err)
-	}
+type statusError struct {
+	url        string
+	statusCode int
+}
 
-	return data, nil
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d for %s", e.statusCode, e.url)
 }
 
-func GetLocationAreaDetails(areaName string) (LocationAreaDetailsResp, error) {
-	fullUrl := "https://pokeapi.co/api/v2/location-area/" + areaName
+func isNotFound(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && se.statusCode == http.StatusNotFound
+}
 
-	if data, exist := cache.Get(fullUrl); exist {
-		var res LocationAreaDetailsResp
-		if err := json.Unmarshal(data, &res); err == nil {
-			return res, nil
+// fetch returns the response body for url, going through the cache so
+// that concurrent requests for the same url hit the network only once.
+// Expired responses, kept in memory or on disk, are revalidated with a
+// conditional request.
+func fetch(url string) ([]byte, error) {
+	resp, err := cache.Do(url, func() (pokecache.Response, error) {
+		stored, fetchedAt, onDisk := disk.Load(url)
+		if onDisk && time.Since(fetchedAt) <= cache.Interval() {
+			return stored, nil
+		}
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return pokecache.Response{}, err
+		}
+
+		stale, hasStale := cache.Stale(url)
+		if !hasStale && onDisk && (stored.ETag != "" || stored.LastModified != "") {
+			stale, hasStale = stored, true
+		}
+		if hasStale {
+			if stale.ETag != "" {
+				req.Header.Set("If-None-Match", stale.ETag)
+			}
+			if stale.LastModified != "" {
+				req.Header.Set("If-Modified-Since", stale.LastModified)
+			}
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return pokecache.Response{}, err
+		}
+		defer res.Body.Close()
+
+		// the disk copy is best effort, a response that can't be stored
+		// is still cached in memory
+		if res.StatusCode == http.StatusNotModified && hasStale {
+			disk.Save(url, stale)
+			return stale, nil
+		}
+
+		if res.StatusCode > 299 {
+			return pokecache.Response{}, &statusError{url: url, statusCode: res.StatusCode}
+		}
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return pokecache.Response{}, fmt.Errorf("failed to read response body: %w", err)
 		}
-	}
 
-	res, err := http.Get(fullUrl)
+		fresh := pokecache.Response{
+			Body:         body,
+			ETag:         res.Header.Get("ETag"),
+			LastModified: res.Header.Get("Last-Modified"),
+		}
+		disk.Save(url, fresh)
+		return fresh, nil
+	})
 	if err != nil {
-		return LocationAreaDetailsResp{}, fmt.Errorf("failed to fetch location area %s: %w", areaName, err)
+		return nil, err
 	}
-	defer res.Body.Close()
+	return resp.Body, nil
+}
 
-	if res.StatusCode > 299 {
-		return LocationAreaDetailsResp{}, fmt.Errorf("location area not found")
+func FetchLocationAreas(url string) (LocationAreaResp, error) {
+	if url == "" {
+		url = baseURL + "location-area/"
 	}
 
-	body, err := io.ReadAll(res.Body)
+	body, err := fetch(url)
 	if err != nil {
-		return LocationAreaDetailsResp{}, fmt.Errorf("failed to read response body: %w", err)
+		return LocationAreaResp{}, fmt.Errorf("failed to fetch location areas: %w", err)
 	}
 
-	cache.Add(fullUrl, body)
-
-	var data LocationAreaDetailsResp
+	var data LocationAreaResp
 	if err := json.Unmarshal(body, &data); err != nil {
-		return LocationAreaDetailsResp{}, fmt.Errorf("failed to parse JSON: %w", err)
+		return LocationAreaResp{}, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
 	return data, nil
 }
 
+func GetLocationAreaDetails(areaName string) (LocationAreaDetailsResp, error) {
+	fullUrl := baseURL + "location-area/" + areaName
+
+	return areaCache.Do(fullUrl, func() (LocationAreaDetailsResp, error) {
+		body, err := fetch(fullUrl)
+		if err != nil {
+			if isNotFound(err) {
+				return LocationAreaDetailsResp{}, fmt.Errorf("location area not found")
+			}
+			return LocationAreaDetailsResp{}, fmt.Errorf("failed to fetch location area %s: %w", areaName, err)
+		}
+
+		var data LocationAreaDetailsResp
+		if err := json.Unmarshal(body, &data); err != nil {
+			return LocationAreaDetailsResp{}, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+
+		return data, nil
+	})
+}
+
+// PokemonURL is the url GetPokemon fetches a Pokemon from.
+func PokemonURL(name string) string {
+	return baseURL + "pokemon/" + url.PathEscape(name)
+}
+
 func GetPokemon(name string) (Pokemon, []byte, error) {
-	fullUrl := "https://pokeapi.co/api/v2/pokemon/" + name
+	fullUrl := PokemonURL(name)
+
+	entry, err := pokemonCache.Do(fullUrl, func() (pokemonEntry, error) {
+		body, err := fetch(fullUrl)
+		if err != nil {
+			if isNotFound(err) {
+				return pokemonEntry{}, ErrPokemonNotFound
+			}
+			return pokemonEntry{}, fmt.Errorf("failed to fetch pokemon %s: %w", name, err)
+		}
 
-	if data, exist := cache.Get(fullUrl); exist {
 		var pokemon Pokemon
-		if err := json.Unmarshal(data, &pokemon); err == nil {
-			return pokemon, data, nil
+		if err := json.Unmarshal(body, &pokemon); err != nil {
+			return pokemonEntry{}, fmt.Errorf("failed to parse JSON: %w", err)
 		}
-	}
 
-	res, err := http.Get(fullUrl)
+		return pokemonEntry{pokemon: pokemon, raw: body}, nil
+	})
 	if err != nil {
-		return Pokemon{}, nil, fmt.Errorf("failed to fetch pokemon %s: %w", name, err)
+		return Pokemon{}, nil, err
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode > 299 {
-		return Pokemon{}, nil, fmt.Errorf("pokemon not found")
-	}
+	return entry.pokemon, entry.raw, nil
+}
 
-	body, err := io.ReadAll(res.Body)
+// ListPokemon returns the name and url of every Pokemon, alternate forms
+// included.
+func ListPokemon() ([]Pokemon, error) {
+	body, err := fetch(baseURL + "pokemon/?limit=100000")
 	if err != nil {
-		return Pokemon{}, nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to fetch the pokemon list: %w", err)
 	}
 
-	cache.Add(fullUrl, body)
-
-	var pokemon Pokemon
-	if err := json.Unmarshal(body, &pokemon); err != nil {
-		return Pokemon{}, nil, fmt.Errorf("failed to parse JSON: %w", err)
+	var data struct {
+		Results []Pokemon `json:"results"`
 	}
-
-	return pokemon, body, nil
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return data.Results, nil
 }
 
 func GetPokemonEncounterAreas(name string) ([]PokemonLocationEncounter, error) {
-	fullUrl := "https://pokeapi.co/api/v2/pokemon/" + name + "/encounters"
+	fullUrl := baseURL + "pokemon/" + name + "/encounters"
 
-	if data, exist := cache.Get(fullUrl); exist {
-		var encounters []PokemonLocationEncounter
-		if err := json.Unmarshal(data, &encounters); err == nil {
-			return encounters, nil
-		}
-	}
-
-	res, err := http.Get(fullUrl)
+	body, err := fetch(fullUrl)
 	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("encounter data not found for %s", name)
+		}
 		return nil, fmt.Errorf("failed to fetch encounters for %s: %w", name, err)
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode > 299 {
-		return nil, fmt.Errorf("encounter data not found for %s", name)
+	var encounters []PokemonLocationEncounter
+	if err := json.Unmarshal(body, &encounters); err != nil {
+		return nil, fmt.Errorf("failed to parse encounter JSON: %w", err)
 	}
 
-	body, err := io.ReadAll(res.Body)
+	return encounters, nil
+}
+
+// GetMoveName returns the identifier of a move, such as "thunder-shock"
+// for move 84.
+func GetMoveName(id int) (string, error) {
+	fullUrl := baseURL + "move/" + strconv.Itoa(id)
+
+	body, err := fetch(fullUrl)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read encounter response: %w", err)
+		if isNotFound(err) {
+			return "", fmt.Errorf("move %d not found", id)
+		}
+		return "", fmt.Errorf("failed to fetch move %d: %w", id, err)
 	}
 
-	cache.Add(fullUrl, body)
+	var move struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &move); err != nil {
+		return "", fmt.Errorf("failed to parse move JSON: %w", err)
+	}
+	return move.Name, nil
+}
 
-	var encounters []PokemonLocationEncounter
-	if err := json.Unmarshal(body, &encounters); err != nil {
-		return nil, fmt.Errorf("failed to parse encounter JSON: %w", err)
+// Warm fetches an API endpoint such as "pokemon/pikachu" into the cache
+// and returns the resolved url along with the size of the cached body.
+func Warm(endpoint string) (string, int, error) {
+	fullUrl := endpoint
+	if !strings.HasPrefix(endpoint, baseURL) {
+		fullUrl = baseURL + strings.TrimPrefix(endpoint, "/")
 	}
 
-	return encounters, nil
+	body, err := fetch(fullUrl)
+	if err != nil {
+		return fullUrl, 0, fmt.Errorf("failed to warm %s: %w", endpoint, err)
+	}
+	return fullUrl, len(body), nil
 }
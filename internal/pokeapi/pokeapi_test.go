@@ -0,0 +1,148 @@
+package pokeapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokecache"
+)
+
+func TestFetchRevalidates(t *testing.T) {
+	const baseTime = 50 * time.Millisecond
+	requests := 0
+	notModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+
+	InitCache(pokecache.NewCache(baseTime))
+
+	body, err := fetch(server.URL)
+	if err != nil || string(body) != "testdata" {
+		t.Fatalf("expected body, got %q (%v)", body, err)
+	}
+
+	// surely expired, and still kept as stale
+	time.Sleep(baseTime * 5 / 2)
+
+	body, err = fetch(server.URL)
+	if err != nil || string(body) != "testdata" {
+		t.Fatalf("expected revalidated body, got %q (%v)", body, err)
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("expected 2 requests with 1 not modified, got %d and %d", requests, notModified)
+	}
+	if _, ok := Cache().Get(server.URL); !ok {
+		t.Errorf("expected revalidated response to be cached again")
+	}
+}
+
+func TestFetchRevalidatesAfterRestart(t *testing.T) {
+	conditional := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("testdata"))
+	}))
+	defer server.Close()
+	SetCacheDir(t.TempDir())
+	defer func() { disk = nil }()
+
+	const baseTime = 5 * time.Millisecond
+	InitCache(pokecache.NewCache(baseTime))
+	if _, err := fetch(server.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new process starts with an empty memory cache, after the stored
+	// response expired
+	time.Sleep(baseTime * 2)
+	InitCache(pokecache.NewCache(baseTime))
+	body, err := fetch(server.URL)
+	if err != nil || string(body) != "testdata" {
+		t.Fatalf("expected the stored body, got %q (%v)", body, err)
+	}
+	if conditional != 1 {
+		t.Errorf("expected the stored response to be revalidated, got %d conditional requests", conditional)
+	}
+}
+
+func TestCacheStatsIncludeDecoded(t *testing.T) {
+	InitCache(pokecache.NewCache(time.Minute))
+	url := PokemonURL("pikachu")
+	decode := func() (pokemonEntry, error) {
+		return pokemonEntry{pokemon: Pokemon{ID: 25, Name: "pikachu"}, raw: []byte("{}")}, nil
+	}
+	for range 3 {
+		if _, err := pokemonCache.Do(url, decode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if stats := CacheStats(); stats.Hits != 2 {
+		t.Errorf("hits do not match. Actual: %d - vs - Expected: %d", stats.Hits, 2)
+	}
+	entries := ListCache(nil)
+	if len(entries) != 1 || entries[0].Key != url {
+		t.Errorf("expected the decoded pokemon to be listed, got %v", entries)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	cases := []struct {
+		status   int
+		expected bool
+	}{
+		{status: http.StatusNotFound, expected: true},
+		{status: http.StatusInternalServerError, expected: false},
+		{status: http.StatusTooManyRequests, expected: false},
+	}
+	for _, c := range cases {
+		err := fmt.Errorf("failed to fetch: %w", &statusError{url: "https://pokeapi.co", statusCode: c.status})
+		if actual := isNotFound(err); actual != c.expected {
+			t.Errorf("status %d: Actual: %v - vs - Expected: %v", c.status, actual, c.expected)
+		}
+	}
+}
+
+func TestForgetURL(t *testing.T) {
+	InitCache(pokecache.NewCache(time.Minute))
+	for _, name := range []string{"mew", "mewtwo"} {
+		Cache().Add(PokemonURL(name), pokecache.Response{Body: []byte(name)})
+	}
+
+	if removed := ForgetURL(PokemonURL("mew")); removed != 1 {
+		t.Errorf("removed does not match. Actual: %d - vs - Expected: %d", removed, 1)
+	}
+	if _, ok := Cache().Get(PokemonURL("mewtwo")); !ok {
+		t.Errorf("expected mewtwo to stay cached")
+	}
+}
+
+func TestPokemonURLEscapes(t *testing.T) {
+	cases := map[string]string{
+		"pikachu":      baseURL + "pokemon/pikachu",
+		"?x":           baseURL + "pokemon/%3Fx",
+		"a/encounters": baseURL + "pokemon/a%2Fencounters",
+	}
+	for name, expected := range cases {
+		if actual := PokemonURL(name); actual != expected {
+			t.Errorf("url does not match. Actual: %s - vs - Expected: %s", actual, expected)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Disk keeps responses in a directory, one file per url, so they outlive
+// the process and can be revalidated on the next start. A nil Disk
+// stores nothing.
+type Disk struct {
+	dir string
+}
+
+type diskEntry struct {
+	URL          string    `json:"url"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+}
+
+func NewDisk(dir string) *Disk {
+	return &Disk{dir: dir}
+}
+
+func (d *Disk) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load returns the stored response for url and when it was fetched.
+// Unreadable files count as missing, as they can always be fetched again.
+func (d *Disk) Load(url string) (Response, time.Time, bool) {
+	if d == nil {
+		return Response{}, time.Time{}, false
+	}
+	entry, err := d.read(d.path(url))
+	if err != nil || entry.URL != url {
+		return Response{}, time.Time{}, false
+	}
+	return Response{Body: entry.Body, ETag: entry.ETag, LastModified: entry.LastModified}, entry.FetchedAt, true
+}
+
+// Save stores the response for url as fetched now. The file is replaced
+// with a rename so a crash never leaves half a response behind.
+func (d *Disk) Save(url string, r Response) error {
+	if d == nil {
+		return nil
+	}
+	data, err := json.Marshal(diskEntry{
+		URL:          url,
+		FetchedAt:    time.Now(),
+		Body:         r.Body,
+		ETag:         r.ETag,
+		LastModified: r.LastModified,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached response: %w", err)
+	}
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	path := d.path(url)
+	tmp, err := os.CreateTemp(d.dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save cached response: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the stored responses whose url matches and returns how
+// many were removed. A nil match clears them all.
+func (d *Disk) Clear(match func(string) bool) int {
+	if d == nil {
+		return 0
+	}
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(d.dir, f.Name())
+		if match != nil {
+			entry, err := d.read(path)
+			if err != nil || !match(entry.URL) {
+				continue
+			}
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (d *Disk) read(path string) (diskEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return diskEntry{}, err
+	}
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return diskEntry{}, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,46 @@
+package pokecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDisk(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "http")
+	disk := NewDisk(dir)
+
+	if _, _, ok := disk.Load("https://example.com/a"); ok {
+		t.Errorf("expected nothing stored yet")
+	}
+
+	before := time.Now()
+	resp := Response{Body: []byte("testdata"), ETag: `"abc"`}
+	if err := disk.Save("https://example.com/a", resp); err != nil {
+		t.Fatal(err)
+	}
+	disk.Save("https://example.com/b", Response{Body: []byte("more")})
+	disk.Save("https://other.com", Response{Body: []byte("x")})
+
+	loaded, fetchedAt, ok := NewDisk(dir).Load("https://example.com/a")
+	if !ok || string(loaded.Body) != "testdata" || loaded.ETag != `"abc"` {
+		t.Errorf("expected the stored response, got %v", loaded)
+	}
+	if fetchedAt.Before(before) {
+		t.Errorf("expected the fetch time to be recorded, got %v", fetchedAt)
+	}
+
+	if removed := disk.Clear(HasPrefix("https://example.com")); removed != 2 {
+		t.Errorf("removed does not match. Actual: %d - vs - Expected: %d", removed, 2)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("expected 1 file left, got %d", len(files))
+	}
+
+	var none *Disk
+	if err := none.Save("https://example.com/a", resp); err != nil {
+		t.Errorf("expected a nil disk to store nothing, got %v", err)
+	}
+}
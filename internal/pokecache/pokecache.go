@@ -1,67 +1,322 @@
 package pokecache
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
-type cacheEntry struct {
+type cacheEntry[V any] struct {
 	createdAt time.Time
-	val       []byte
+	val       V
 }
 
-type Cache struct {
+type call[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+}
+
+// staleIntervals is how many intervals an expired value is kept for
+// revalidation. Reaping runs once an interval, so two leave at least a
+// whole interval in which it is stale.
+const staleIntervals = 2
+
+// errPanicked is what callers sharing a fetch get when it panics.
+var errPanicked = errors.New("fetch panicked")
+
+type Stats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+	Entries   int
+	Stale     int
+	// Bytes counts fresh and stale values, as both are held in memory.
+	Bytes int
+}
+
+type EntryInfo[K comparable] struct {
+	Key  K
+	Size int
+	Age  time.Duration
+}
+
+// Cache holds values of type V keyed by K for a fixed interval. The size
+// function, when set, is used to keep the byte totals in Stats.
+type Cache[K comparable, V any] struct {
 	mu       sync.Mutex
-	items    map[string]cacheEntry
+	items    map[K]cacheEntry[V]
+	inflight map[K]*call[V]
+	stale    map[K]cacheEntry[V]
 	interval time.Duration
+	size     func(V) int
+	retain   func(V) bool
+	stats    Stats
+}
+
+// Response is a cached HTTP response body along with the validators
+// needed to revalidate it once it expires.
+type Response struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+}
+
+// BytesCache is the cache used for raw HTTP responses.
+type BytesCache = Cache[string, Response]
+
+func New[K comparable, V any](interval time.Duration, size func(V) int) *Cache[K, V] {
+	return newCache[K](interval, size, nil)
+}
+
+// NewCache creates the HTTP response cache. Expired responses that carry
+// an ETag or Last-Modified header are kept aside for a while and can be
+// fetched with Stale for a conditional request.
+func NewCache(interval time.Duration) *BytesCache {
+	size := func(r Response) int {
+		return len(r.Body)
+	}
+	retain := func(r Response) bool {
+		return r.ETag != "" || r.LastModified != ""
+	}
+	return newCache[string](interval, size, retain)
 }
 
-func NewCache(interval time.Duration) *Cache {
-	c := &Cache{
-		items:    make(map[string]cacheEntry),
+func newCache[K comparable, V any](interval time.Duration, size func(V) int, retain func(V) bool) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items:    make(map[K]cacheEntry[V]),
+		inflight: make(map[K]*call[V]),
+		stale:    make(map[K]cacheEntry[V]),
 		interval: interval,
+		size:     size,
+		retain:   retain,
 	}
 
 	go c.reapLoop()
 
 	return c
+}
 
+// HasPrefix matches string keys starting with prefix, for use with
+// List and Clear.
+func HasPrefix(prefix string) func(string) bool {
+	return func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	}
+}
+
+// Is matches exactly key, for use with List and Clear.
+func Is[K comparable](key K) func(K) bool {
+	return func(k K) bool {
+		return k == key
+	}
 }
 
-func (c *Cache) Add(key string, val []byte) {
+func (c *Cache[K, V]) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *Cache[K, V]) sizeOf(val V) int {
+	if c.size == nil {
+		return 0
+	}
+	return c.size(val)
+}
+
+func (c *Cache[K, V]) Add(key K, val V) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items[key] = cacheEntry{
+	c.add(key, val)
+}
+
+func (c *Cache[K, V]) add(key K, val V) {
+	if old, ok := c.items[key]; ok {
+		c.stats.Bytes -= c.sizeOf(old.val)
+	}
+	c.removeStale(key)
+	c.items[key] = cacheEntry[V]{
 		createdAt: time.Now(),
 		val:       val,
 	}
+	c.stats.Bytes += c.sizeOf(val)
+}
+
+func (c *Cache[K, V]) remove(key K) {
+	if entry, ok := c.items[key]; ok {
+		c.stats.Bytes -= c.sizeOf(entry.val)
+		delete(c.items, key)
+	}
 }
 
-func (c *Cache) Get(key string) ([]byte, bool) {
+func (c *Cache[K, V]) removeStale(key K) {
+	if entry, ok := c.stale[key]; ok {
+		c.stats.Bytes -= c.sizeOf(entry.val)
+		delete(c.stale, key)
+	}
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	entry, ok := c.items[key]
 	if !ok {
-		return nil, false
+		c.stats.Misses++
+		var zero V
+		return zero, false
 	}
+	c.stats.Hits++
 	return entry.val, true
 }
 
-func (c *Cache) reapLoop() {
+// Do returns the cached value for key, or calls fetch to produce it.
+// Concurrent callers asking for the same missing key share a single
+// fetch and a single cache write.
+func (c *Cache[K, V]) Do(key K, fetch func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if entry, ok := c.items[key]; ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return entry.val, nil
+	}
+	if cl, ok := c.inflight[key]; ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		cl.wg.Wait()
+		return cl.val, cl.err
+	}
+
+	c.stats.Misses++
+	cl := &call[V]{}
+	cl.wg.Add(1)
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	// the call is finished even if fetch panics, so later callers for
+	// the key don't wait on it forever
+	fetched := false
+	defer func() {
+		if !fetched {
+			cl.err = errPanicked
+		}
+		c.mu.Lock()
+		if cl.err == nil {
+			c.add(key, cl.val)
+		}
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		cl.wg.Done()
+	}()
+
+	cl.val, cl.err = fetch()
+	fetched = true
+	return cl.val, cl.err
+}
+
+// Stale returns an expired value kept for revalidation. Adding the key
+// again, for example after a 304 response, makes it fresh.
+func (c *Cache[K, V]) Stale(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.stale[key]
+	return entry.val, ok
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.items)
+	stats.Stale = len(c.stale)
+	return stats
+}
+
+// List returns the entries whose key matches, oldest first. A nil match
+// lists every entry.
+func (c *Cache[K, V]) List(match func(K) bool) []EntryInfo[K] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// ages are taken from one clock reading so they order entries the
+	// same way their creation times do
+	now := time.Now()
+	entries := []EntryInfo[K]{}
+	for key, entry := range c.items {
+		if match != nil && !match(key) {
+			continue
+		}
+		entries = append(entries, EntryInfo[K]{
+			Key:  key,
+			Size: c.sizeOf(entry.val),
+			Age:  now.Sub(entry.createdAt),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Age != entries[j].Age {
+			return entries[i].Age > entries[j].Age
+		}
+		return fmt.Sprint(entries[i].Key) < fmt.Sprint(entries[j].Key)
+	})
+	return entries
+}
+
+// Clear removes every entry whose key matches and returns how many were
+// removed. A nil match clears the whole cache.
+func (c *Cache[K, V]) Clear(match func(K) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key := range c.items {
+		if match == nil || match(key) {
+			c.remove(key)
+			removed++
+		}
+	}
+	for key := range c.stale {
+		if match == nil || match(key) {
+			c.removeStale(key)
+		}
+	}
+	return removed
+}
+
+func (c *Cache[K, V]) reapLoop() {
 	ticker := time.NewTicker(c.interval)
 	defer ticker.Stop()
 
 	for {
 		<-ticker.C
+		c.reap(time.Now())
+	}
+}
 
-		c.mu.Lock()
-		for key, item := range c.items {
-			if time.Since(item.createdAt) > c.interval {
-				delete(c.items, key)
+// reap evicts the entries older than the interval, keeping the ones to
+// retain as stale, and drops stale entries once they are staleIntervals
+// older than that.
+func (c *Cache[K, V]) reap(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.stale {
+		if now.Sub(entry.createdAt) > (1+staleIntervals)*c.interval {
+			c.removeStale(key)
+		}
+	}
+	for key, item := range c.items {
+		if now.Sub(item.createdAt) > c.interval {
+			c.remove(key)
+			c.stats.Evictions++
+			if c.retain != nil && c.retain(item.val) {
+				c.stale[key] = item
+				c.stats.Bytes += c.sizeOf(item.val)
 			}
 		}
-		c.mu.Unlock()
 	}
 }
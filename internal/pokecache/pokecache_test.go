@@ -1,11 +1,19 @@
 package pokecache
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
 
+func newBytesCache(interval time.Duration) *Cache[string, []byte] {
+	return New[string](interval, func(val []byte) int {
+		return len(val)
+	})
+}
+
 func TestAddGet(t *testing.T) {
 	const interval = 5 * time.Second
 	cases := []struct {
@@ -24,7 +32,7 @@ func TestAddGet(t *testing.T) {
 
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
-			cache := NewCache(interval)
+			cache := newBytesCache(interval)
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -42,7 +50,7 @@ func TestAddGet(t *testing.T) {
 func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
-	cache := NewCache(baseTime)
+	cache := newBytesCache(baseTime)
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -59,3 +67,217 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestDoCoalesces(t *testing.T) {
+	cache := newBytesCache(5 * time.Second)
+
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return []byte("testdata"), nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([][]byte, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			val, err := cache.Do("https://example.com", fetch)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results[i] = val
+		}(i)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected 1 fetch, got %d", calls)
+	}
+	for _, val := range results {
+		if string(val) != "testdata" {
+			t.Errorf("expected to find value")
+		}
+	}
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected to find key")
+	}
+}
+
+func TestDoError(t *testing.T) {
+	cache := newBytesCache(5 * time.Second)
+	_, err := cache.Do("https://example.com", func() ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected failed fetch to not be cached")
+	}
+}
+
+func TestDoPanic(t *testing.T) {
+	cache := newBytesCache(5 * time.Second)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected the panic to reach the caller")
+			}
+		}()
+		cache.Do("https://example.com", func() ([]byte, error) {
+			panic("boom")
+		})
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		val, err := cache.Do("https://example.com", func() ([]byte, error) {
+			return []byte("testdata"), nil
+		})
+		if err != nil || string(val) != "testdata" {
+			t.Errorf("expected a fresh fetch, got %q (%v)", val, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the next caller to not wait on the panicked fetch")
+	}
+}
+
+func TestStats(t *testing.T) {
+	cache := newBytesCache(5 * time.Second)
+	cache.Add("https://example.com/a", []byte("testdata"))
+	cache.Add("https://example.com/b", []byte("moretestdata"))
+	cache.Add("https://other.com", []byte("x"))
+
+	cache.Get("https://example.com/a")
+	cache.Get("https://example.com/missing")
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+	}
+	if stats.Entries != 3 || stats.Bytes != 21 {
+		t.Errorf("expected 3 entries and 21 bytes, got %d and %d", stats.Entries, stats.Bytes)
+	}
+
+	entries := cache.List(HasPrefix("https://example.com"))
+	if len(entries) != 2 || entries[0].Key != "https://example.com/a" {
+		t.Errorf("expected 2 sorted entries for prefix, got %v", entries)
+	}
+
+	if removed := cache.Clear(Is("https://example.com/a")); removed != 1 {
+		t.Errorf("expected to clear exactly 1 entry, got %d", removed)
+	}
+	if removed := cache.Clear(HasPrefix("https://example.com")); removed != 1 {
+		t.Errorf("expected to clear 1 entry, got %d", removed)
+	}
+	stats = cache.Stats()
+	if stats.Entries != 1 || stats.Bytes != 1 {
+		t.Errorf("expected 1 entry and 1 byte after clear, got %d and %d", stats.Entries, stats.Bytes)
+	}
+}
+
+func TestEvictionStats(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := newBytesCache(baseTime)
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(baseTime * 3)
+
+	stats := cache.Stats()
+	if stats.Evictions != 1 || stats.Bytes != 0 {
+		t.Errorf("expected 1 eviction and 0 bytes, got %d and %d", stats.Evictions, stats.Bytes)
+	}
+}
+
+func TestTypedCache(t *testing.T) {
+	type pokemon struct {
+		ID   int
+		Name string
+	}
+
+	cache := New[int, pokemon](5*time.Second, nil)
+	cache.Add(25, pokemon{ID: 25, Name: "pikachu"})
+
+	val, ok := cache.Get(25)
+	if !ok {
+		t.Errorf("expected to find key")
+		return
+	}
+	if val.Name != "pikachu" {
+		t.Errorf("expected to find value, got %v", val)
+	}
+
+	fetched, err := cache.Do(1, func() (pokemon, error) {
+		return pokemon{ID: 1, Name: "bulbasaur"}, nil
+	})
+	if err != nil || fetched.Name != "bulbasaur" {
+		t.Errorf("expected fetched value, got %v (%v)", fetched, err)
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Bytes != 0 {
+		t.Errorf("expected 2 entries and no byte total, got %d and %d", stats.Entries, stats.Bytes)
+	}
+}
+
+func TestStaleResponses(t *testing.T) {
+	const interval = time.Minute
+	cache := NewCache(interval)
+	cache.Add("https://example.com/etag", Response{Body: []byte("testdata"), ETag: `"abc"`})
+	cache.Add("https://example.com/plain", Response{Body: []byte("testdata")})
+
+	now := time.Now()
+	cache.reap(now.Add(2 * interval))
+
+	if _, ok := cache.Get("https://example.com/etag"); ok {
+		t.Errorf("expected to not find key")
+	}
+	stale, ok := cache.Stale("https://example.com/etag")
+	if !ok || stale.ETag != `"abc"` {
+		t.Errorf("expected stale response with validator, got %v", stale)
+	}
+	if _, ok := cache.Stale("https://example.com/plain"); ok {
+		t.Errorf("expected response without validators to be dropped")
+	}
+	if stats := cache.Stats(); stats.Stale != 1 || stats.Bytes != 8 {
+		t.Errorf("expected 1 stale entry of 8 bytes, got %d and %d", stats.Stale, stats.Bytes)
+	}
+
+	cache.Add("https://example.com/etag", stale)
+	if _, ok := cache.Stale("https://example.com/etag"); ok {
+		t.Errorf("expected refreshed response to no longer be stale")
+	}
+	if stats := cache.Stats(); stats.Bytes != 8 {
+		t.Errorf("expected the refreshed response to be counted once, got %d bytes", stats.Bytes)
+	}
+}
+
+func TestStaleResponsesExpire(t *testing.T) {
+	const interval = time.Minute
+	cache := NewCache(interval)
+	cache.Add("https://example.com/etag", Response{Body: []byte("testdata"), ETag: `"abc"`})
+
+	now := time.Now()
+	cache.reap(now.Add(2 * interval))
+	cache.reap(now.Add((1+staleIntervals)*interval + time.Second))
+
+	if _, ok := cache.Stale("https://example.com/etag"); ok {
+		t.Errorf("expected the stale response to be dropped")
+	}
+	if stats := cache.Stats(); stats.Stale != 0 || stats.Bytes != 0 {
+		t.Errorf("expected no stale entries and 0 bytes, got %d and %d", stats.Stale, stats.Bytes)
+	}
+}
@@ -0,0 +1,143 @@
+package pokedex
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormats lists the formats Export writes. Markdown is meant for
+// sharing and can't be imported back.
+var ExportFormats = []string{"json", "csv", "md"}
+
+var csvHeader = []string{
+	"instance_id", "species_id", "species", "nickname", "level", "nature",
+	"shiny", "ball", "location", "attempts", "caught_at",
+	"ability", "item", "moves", "evs", "ivs", "ot", "ot_id", "dvs",
+}
+
+// Export writes the Dex and the Pokemon you own in format.
+func Export(w io.Writer, format string, dex []DexEntry, pokemon []Pokemon) error {
+	switch format {
+	case "json":
+		return exportJSON(w, dex, pokemon)
+	case "csv":
+		return exportCSV(w, pokemon)
+	case "md":
+		return exportMarkdown(w, dex, pokemon)
+	}
+	return fmt.Errorf("unknown export format %q, use one of %s", format, strings.Join(ExportFormats, ", "))
+}
+
+// exportJSON writes a save file without the undo journal, so an export
+// can be imported by any Pokedex that reads saves of this version.
+func exportJSON(w io.Writer, dex []DexEntry, pokemon []Pokemon) error {
+	save := newSaveFile()
+	save.Dex = append(save.Dex, dex...)
+	save.Pokemon = append(save.Pokemon, pokemon...)
+	for _, p := range pokemon {
+		save.NextInstanceID = max(save.NextInstanceID, p.InstanceID+1)
+	}
+
+	data, err := encodeSave(save)
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func exportCSV(w io.Writer, pokemon []Pokemon) error {
+	out := csv.NewWriter(w)
+	out.Write(csvHeader)
+	for _, p := range pokemon {
+		caughtAt := ""
+		if !p.CaughtAt.IsZero() {
+			caughtAt = p.CaughtAt.Format(time.RFC3339Nano)
+		}
+		out.Write([]string{
+			strconv.Itoa(p.InstanceID),
+			strconv.Itoa(p.SpeciesID),
+			p.Species,
+			p.Nickname,
+			strconv.Itoa(p.Level),
+			p.Nature,
+			strconv.FormatBool(p.Shiny),
+			p.Ball,
+			p.Location,
+			strconv.Itoa(p.Attempts),
+			caughtAt,
+			p.Ability,
+			p.Item,
+			strings.Join(slices.DeleteFunc(slices.Clone(p.Moves[:]), func(move string) bool {
+				return move == ""
+			}), "/"),
+			formatCSVSpread(p.EVs),
+			formatCSVSpread(p.IVs),
+			p.OT,
+			strconv.Itoa(p.OTID),
+			formatCSVSpread(p.DVs),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// formatCSVSpread writes a stat spread as six numbers in HP/Atk/Def/SpA/
+// SpD/Spe order, leaving it empty when the spread isn't known.
+func formatCSVSpread(spread StatSpread) string {
+	if spread == (StatSpread{}) {
+		return ""
+	}
+	values := make([]string, 0, len(showdownStats))
+	for _, stat := range showdownStats {
+		values = append(values, strconv.Itoa(*stat.field(&spread)))
+	}
+	return strings.Join(values, "/")
+}
+
+func exportMarkdown(w io.Writer, dex []DexEntry, pokemon []Pokemon) error {
+	seen, caught := 0, 0
+	types := make(map[string]string)
+	for _, d := range dex {
+		seen++
+		if d.Caught {
+			caught++
+		}
+		if d.Info != nil {
+			types[d.Name] = strings.Join(d.Info.Types, "/")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Pokedex\n\n")
+	fmt.Fprintf(&b, "%d Pokemon owned, %d species caught, %d seen.\n\n", len(pokemon), caught, seen)
+	b.WriteString("| ID | No. | Species | Nickname | Type | Lv | Nature | Ball | Caught | Location |\n")
+	b.WriteString("|---:|---:|---|---|---|---:|---|---|---|---|\n")
+	for _, p := range pokemon {
+		species := p.Species
+		if p.Shiny {
+			species += " ✨"
+		}
+		caughtAt := ""
+		if !p.CaughtAt.IsZero() {
+			caughtAt = p.CaughtAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(&b, "| %d | %03d | %s | %s | %s | %d | %s | %s | %s | %s |\n",
+			p.InstanceID, p.SpeciesID, species, markdownEscape(p.Nickname), types[p.Species],
+			p.Level, p.Nature, p.Ball, caughtAt, p.Location)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscape keeps user text such as nicknames from breaking the
+// table.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
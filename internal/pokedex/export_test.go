@@ -0,0 +1,131 @@
+package pokedex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportFixture() ([]DexEntry, []Pokemon) {
+	caughtAt := time.Date(2026, 10, 19, 14, 3, 0, 123456789, time.UTC)
+	dex := []DexEntry{
+		{ID: 7, Name: "squirtle"},
+		{ID: 25, Name: "pikachu", Caught: true, Info: &SpeciesInfo{Types: []string{"electric"}}},
+	}
+	pokemon := []Pokemon{
+		{InstanceID: 1, SpeciesID: 25, Species: "pikachu", Nickname: "Sparky", Level: 7, CaughtAt: caughtAt, Ball: "Poké Ball"},
+		{InstanceID: 2, SpeciesID: 25, Species: "pikachu", Level: 12, CaughtAt: caughtAt.Add(time.Hour), Shiny: true},
+		{
+			InstanceID: 3, SpeciesID: 25, Species: "pikachu", Level: 50, Nature: "jolly",
+			Ability: "static", Item: "light-ball", Moves: [4]string{"volt-tackle", "iron-tail"},
+			EVs: StatSpread{Attack: 252, SpDef: 4, Speed: 252},
+			IVs: StatSpread{HP: 31, Attack: 31, Defense: 31, SpAtk: 0, SpDef: 31, Speed: 31},
+		},
+		{
+			InstanceID: 4, SpeciesID: 25, Species: "pikachu", Level: 5, CaughtAt: caughtAt.Add(2 * time.Hour),
+			OT: "RED", OTID: 12345, DVs: StatSpread{HP: 8, Attack: 15, Defense: 3, SpAtk: 10, SpDef: 10, Speed: 12},
+		},
+	}
+	return dex, pokemon
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	dex, pokemon := exportFixture()
+
+	for _, format := range ImportFormats {
+		var out bytes.Buffer
+		if err := Export(&out, format, dex, pokemon); err != nil {
+			t.Fatalf("%s: unexpected error exporting: %v", format, err)
+		}
+		importedDex, imported, err := ParseImport(out.Bytes(), format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error importing: %v", format, err)
+		}
+
+		if len(imported) != len(pokemon) {
+			t.Fatalf("%s: len of actual not the same as expected. Actual: %d - vs - Expected: %d", format, len(imported), len(pokemon))
+		}
+		for i := range imported {
+			if !imported[i].CaughtAt.Equal(pokemon[i].CaughtAt) {
+				t.Errorf("%s: caught at does not match. Actual: %v - vs - Expected: %v", format, imported[i].CaughtAt, pokemon[i].CaughtAt)
+			}
+			imported[i].CaughtAt = pokemon[i].CaughtAt
+			if imported[i] != pokemon[i] {
+				t.Errorf("%s: pokemon does not match. Actual: %v - vs - Expected: %v", format, imported[i], pokemon[i])
+			}
+		}
+		if format == "json" && len(importedDex) != len(dex) {
+			t.Errorf("json: expected the dex to round trip, got %v", importedDex)
+		}
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	dex, pokemon := exportFixture()
+	pokemon[0].Nickname = "a|b"
+
+	var out bytes.Buffer
+	if err := Export(&out, "md", dex, pokemon); err != nil {
+		t.Fatal(err)
+	}
+	expected := `| 1 | 025 | pikachu | a\|b | electric | 7 | `
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("expected markdown to contain %q, got:\n%s", expected, out.String())
+	}
+	if _, _, err := ParseImport(out.Bytes(), "md"); err == nil {
+		t.Errorf("expected an error importing markdown")
+	}
+}
+
+func TestImportMerges(t *testing.T) {
+	dex, pokemon := exportFixture()
+	store := NewMemoryStore()
+
+	// a different Pokemon already holds the nickname
+	bulbasaur, _ := store.Add(Pokemon{SpeciesID: 1, Species: "bulbasaur"}, nil)
+	store.SetNickname(bulbasaur.InstanceID, "sparky")
+
+	report, err := store.Import(dex, pokemon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Added != 4 || report.Seen != 1 || len(report.Conflicts) != 1 {
+		t.Errorf("unexpected report of the first import: %+v", report)
+	}
+	if p, _, _ := store.Get("#2"); p.Nickname != "" || p.Species != "pikachu" {
+		t.Errorf("expected the clashing nickname to be dropped, got %v", p)
+	}
+
+	for _, format := range ImportFormats {
+		var out bytes.Buffer
+		if err := Export(&out, format, dex, pokemon); err != nil {
+			t.Fatal(err)
+		}
+		importedDex, imported, err := ParseImport(out.Bytes(), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err = store.Import(importedDex, imported)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Added != 0 || report.Duplicates != 4 {
+			t.Errorf("%s: expected importing twice to add nothing, got %+v", format, report)
+		}
+	}
+
+	report, err = store.Import(nil, []Pokemon{{SpeciesID: 4, Species: "charmander", Nickname: "Pikachu"}})
+	if err != nil || report.Added != 1 || len(report.Conflicts) != 1 {
+		t.Fatalf("expected charmander to be added without its nickname, got %+v (%v)", report, err)
+	}
+	if p, _, _ := store.Get("charmander"); p.Nickname != "" {
+		t.Errorf("expected a species name to be dropped as a nickname, got %v", p)
+	}
+	if undone, err := store.Undo(); err != nil || undone.Kind != "import" {
+		t.Fatalf("expected to undo the import, got %v (%v)", undone, err)
+	}
+	if ok, _ := store.Has("charmander"); ok {
+		t.Errorf("expected undo to remove the imported charmander")
+	}
+}
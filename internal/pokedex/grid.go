@@ -0,0 +1,130 @@
+package pokedex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/theme"
+)
+
+const (
+	gridColumns = 4
+	gridName    = 12
+)
+
+type Generation struct {
+	Number int
+	First  int
+	Last   int
+}
+
+func (g Generation) Size() int {
+	return g.Last - g.First + 1
+}
+
+func (g Generation) Contains(id int) bool {
+	return id >= g.First && id <= g.Last
+}
+
+var Generations = []Generation{
+	{Number: 1, First: 1, Last: 151},
+	{Number: 2, First: 152, Last: 251},
+	{Number: 3, First: 252, Last: 386},
+	{Number: 4, First: 387, Last: 493},
+	{Number: 5, First: 494, Last: 649},
+	{Number: 6, First: 650, Last: 721},
+	{Number: 7, First: 722, Last: 809},
+	{Number: 8, First: 810, Last: 905},
+	{Number: 9, First: 906, Last: 1025},
+}
+
+// GenerationOf returns the generation a national Pokedex ID belongs to.
+func GenerationOf(id int) (Generation, bool) {
+	for _, g := range Generations {
+		if g.Contains(id) {
+			return g, true
+		}
+	}
+	return Generation{}, false
+}
+
+type Completion struct {
+	Seen   int
+	Caught int
+	Total  int
+}
+
+func (c Completion) SeenPercent() float64 {
+	return percent(c.Seen, c.Total)
+}
+
+func (c Completion) CaughtPercent() float64 {
+	return percent(c.Caught, c.Total)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// CompletionOf counts the seen and caught species of a generation. A
+// caught species also counts as seen.
+func CompletionOf(dex []DexEntry, g Generation) Completion {
+	c := Completion{Total: g.Size()}
+	for _, d := range dex {
+		if !g.Contains(d.ID) {
+			continue
+		}
+		c.Seen++
+		if d.Caught {
+			c.Caught++
+		}
+	}
+	return c
+}
+
+// RenderGrid lays out every species of a generation in numbered columns:
+// caught species in color, seen ones greyed out and the rest as ???.
+func RenderGrid(dex []DexEntry, g Generation) []string {
+	byID := make(map[int]DexEntry, len(dex))
+	for _, d := range dex {
+		if g.Contains(d.ID) {
+			byID[d.ID] = d
+		}
+	}
+
+	styles := theme.Current()
+	var lines []string
+	var line strings.Builder
+	for id := g.First; id <= g.Last; id++ {
+		label := "???"
+		var style *theme.Style
+		if d, ok := byID[id]; ok {
+			label = d.Name
+			style = &styles.Seen
+			if d.Caught {
+				style = &styles.Caught
+			}
+		}
+		if len(label) > gridName {
+			label = label[:gridName]
+		}
+
+		cell := fmt.Sprintf("%03d %-*s", id, gridName, label)
+		if style != nil {
+			cell = style.Paint(terminal.CurrentDepth(), cell)
+		}
+		line.WriteString(cell)
+
+		if (id-g.First+1)%gridColumns == 0 || id == g.Last {
+			lines = append(lines, line.String())
+			line.Reset()
+		} else {
+			line.WriteString("  ")
+		}
+	}
+	return lines
+}
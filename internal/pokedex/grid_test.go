@@ -0,0 +1,58 @@
+package pokedex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+func TestCompletionOf(t *testing.T) {
+	dex := []DexEntry{
+		{ID: 1, Name: "bulbasaur", Caught: true},
+		{ID: 16, Name: "pidgey"},
+		{ID: 152, Name: "chikorita", Caught: true},
+	}
+
+	gen1 := Generations[0]
+	c := CompletionOf(dex, gen1)
+	if c.Seen != 2 || c.Caught != 1 || c.Total != 151 {
+		t.Errorf("expected 2 seen and 1 caught of 151, got %+v", c)
+	}
+
+	g, ok := GenerationOf(152)
+	if !ok || g.Number != 2 {
+		t.Errorf("expected 152 to be generation 2, got %v", g)
+	}
+}
+
+func TestRenderGrid(t *testing.T) {
+	dex := []DexEntry{
+		{ID: 1, Name: "bulbasaur", Caught: true},
+		{ID: 2, Name: "ivysaur"},
+	}
+	gen := Generation{Number: 1, First: 1, Last: 6}
+
+	lines := RenderGrid(dex, gen)
+	if len(lines) != 2 {
+		t.Fatalf("expected 6 species over 2 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "\x1b[1m\x1b[38;2;120;200;80m001 bulbasaur") {
+		t.Errorf("expected caught species in color, got %q", lines[0])
+	}
+	if !strings.Contains(lines[0], "\x1b[2m\x1b[38;2;128;128;128m002 ivysaur") {
+		t.Errorf("expected seen species greyed out, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "006 ???") {
+		t.Errorf("expected unknown species as ???, got %q", lines[1])
+	}
+
+	terminal.SetDepth(terminal.NoColor)
+	defer terminal.SetDepth(terminal.TrueColor)
+	if lines := RenderGrid(dex, gen); strings.Contains(lines[0], "\x1b") {
+		t.Errorf("expected no escapes without color, got %q", lines[0])
+	}
+	if actual := TypeBadge("electric"); actual != "Electric" {
+		t.Errorf("badge does not match. Actual: %q - vs - Expected: %q", actual, "Electric")
+	}
+}
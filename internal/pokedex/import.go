@@ -0,0 +1,264 @@
+package pokedex
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportFormats lists the formats ParseImport reads.
+var ImportFormats = []string{"json", "csv"}
+
+// ImportReport describes what merging an import changed and what it
+// could not merge cleanly.
+type ImportReport struct {
+	Added      int
+	Duplicates int
+	Seen       int
+	Conflicts  []string
+}
+
+// ParseImport reads an export. JSON exports, and save files of any
+// version, keep the seen species; CSV only holds the Pokemon you own.
+func ParseImport(data []byte, format string) ([]DexEntry, []Pokemon, error) {
+	switch format {
+	case "json":
+		save, _, err := decodeSave(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read import: %w", err)
+		}
+		return save.Dex, save.Pokemon, nil
+	case "csv":
+		pokemon, err := parseCSV(data)
+		return nil, pokemon, err
+	}
+	return nil, nil, fmt.Errorf("can't import format %q, use one of %s", format, strings.Join(ImportFormats, ", "))
+}
+
+func parseCSV(data []byte) ([]Pokemon, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("import is empty")
+	}
+
+	// columns are matched by name so hand-edited files may reorder them
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"species_id", "species"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("import is missing the %s column", required)
+		}
+	}
+
+	pokemon := []Pokemon{}
+	for n, row := range rows[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		number := func(name string) (int, error) {
+			value := field(name)
+			if value == "" {
+				return 0, nil
+			}
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid %s %q", n+2, name, value)
+			}
+			return i, nil
+		}
+
+		p := Pokemon{
+			Species:  strings.ToLower(field("species")),
+			Nickname: field("nickname"),
+			Nature:   field("nature"),
+			Ball:     field("ball"),
+			Location: field("location"),
+			Shiny:    field("shiny") == "true",
+			Ability:  field("ability"),
+			Item:     field("item"),
+			OT:       field("ot"),
+		}
+		if p.InstanceID, err = number("instance_id"); err != nil {
+			return nil, err
+		}
+		if p.SpeciesID, err = number("species_id"); err != nil {
+			return nil, err
+		}
+		if p.Level, err = number("level"); err != nil {
+			return nil, err
+		}
+		if p.Attempts, err = number("attempts"); err != nil {
+			return nil, err
+		}
+		if p.OTID, err = number("ot_id"); err != nil {
+			return nil, err
+		}
+		if value := field("moves"); value != "" {
+			moves := strings.Split(value, "/")
+			if len(moves) > len(p.Moves) {
+				return nil, fmt.Errorf("line %d: invalid moves %q, a Pokemon knows at most %d", n+2, value, len(p.Moves))
+			}
+			copy(p.Moves[:], moves)
+		}
+		for _, spread := range []struct {
+			name  string
+			value *StatSpread
+		}{{"evs", &p.EVs}, {"ivs", &p.IVs}, {"dvs", &p.DVs}} {
+			if *spread.value, err = parseCSVSpread(field(spread.name)); err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %w", n+2, spread.name, err)
+			}
+		}
+		if value := field("caught_at"); value != "" {
+			if p.CaughtAt, err = time.Parse(time.RFC3339Nano, value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid caught_at %q", n+2, value)
+			}
+		}
+		pokemon = append(pokemon, p)
+	}
+	return pokemon, nil
+}
+
+// parseCSVSpread reads a stat spread written by formatCSVSpread.
+func parseCSVSpread(value string) (StatSpread, error) {
+	var spread StatSpread
+	if value == "" {
+		return spread, nil
+	}
+	values := strings.Split(value, "/")
+	if len(values) != len(showdownStats) {
+		return StatSpread{}, fmt.Errorf("expected %d numbers, got %q", len(showdownStats), value)
+	}
+	for i, stat := range showdownStats {
+		n, err := strconv.Atoi(strings.TrimSpace(values[i]))
+		if err != nil {
+			return StatSpread{}, fmt.Errorf("expected %d numbers, got %q", len(showdownStats), value)
+		}
+		*stat.field(&spread) = n
+	}
+	return spread, nil
+}
+
+// sameCatch reports whether two Pokemon are the same catch, which is how
+// importing a file twice is detected. Team pastes have no catch time, so
+// their sets are told apart by everything else they carry.
+func sameCatch(a, b Pokemon) bool {
+	return a.Species == b.Species && a.CaughtAt.Equal(b.CaughtAt) &&
+		a.Level == b.Level && a.Shiny == b.Shiny && a.Nature == b.Nature &&
+		a.Ability == b.Ability && a.Item == b.Item &&
+		a.EVs == b.EVs && a.IVs == b.IVs && a.Moves == b.Moves &&
+		a.OT == b.OT && a.OTID == b.OTID && a.DVs == b.DVs
+}
+
+// merge adds imported Pokemon as new instances and the imported Dex to
+// the save. Pokemon you already own are skipped, and nicknames that
+// would clash are dropped and reported.
+func (save *SaveFile) merge(dex []DexEntry, pokemon []Pokemon) ImportReport {
+	report := ImportReport{}
+
+	touched := make([]Pokemon, 0, len(dex)+len(pokemon))
+	touched = append(touched, pokemon...)
+	for _, d := range dex {
+		// only the species matters for the snapshot
+		touched = append(touched, Pokemon{SpeciesID: d.ID, Species: d.Name})
+	}
+	snapshot, absent := save.dexSnapshot(touched)
+
+	matched := make(map[int]bool)
+	added := []Pokemon{}
+	for _, p := range pokemon {
+		if p.Species == "" || p.SpeciesID < 1 {
+			report.Conflicts = append(report.Conflicts, fmt.Sprintf("skipped #%d: missing species", p.InstanceID))
+			continue
+		}
+
+		if existing, ok := save.findCatch(p, matched); ok {
+			matched[existing.InstanceID] = true
+			report.Duplicates++
+			if p.Nickname != "" && p.Nickname != existing.Nickname {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf(
+					"%s #%d is already in your collection, kept its nickname %q over %q",
+					existing.Species, existing.InstanceID, existing.Nickname, p.Nickname))
+			}
+			continue
+		}
+
+		if p.Nickname != "" {
+			if err := ValidateNickname(p.Nickname); err != nil {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("%s: dropped nickname %q: %v", p.Species, p.Nickname, err))
+				p.Nickname = ""
+			} else if save.isSpecies(p.Nickname) || slices.ContainsFunc(dex, func(d DexEntry) bool { return strings.EqualFold(d.Name, p.Nickname) }) {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf("%s: dropped nickname %q, it is the name of a species", p.Species, p.Nickname))
+				p.Nickname = ""
+			} else if owner, ok := save.findNickname(p.Nickname); ok {
+				report.Conflicts = append(report.Conflicts, fmt.Sprintf(
+					"%s: dropped nickname %q, it is taken by %s #%d", p.Species, p.Nickname, owner.Species, owner.InstanceID))
+				p.Nickname = ""
+			}
+		}
+
+		var info *SpeciesInfo
+		for _, d := range dex {
+			if d.Name == p.Species {
+				info = d.Info
+			}
+		}
+		if i := save.findDex(p.Species); i >= 0 && save.Dex[i].Info != nil {
+			info = nil
+		}
+
+		p = save.add(p, info)
+		matched[p.InstanceID] = true
+		added = append(added, p)
+	}
+	report.Added = len(added)
+
+	for _, d := range dex {
+		i := save.findDex(d.Name)
+		if i < 0 {
+			save.Dex = append(save.Dex, d)
+			report.Seen++
+			continue
+		}
+		if d.Caught {
+			save.Dex[i].Caught = true
+		}
+		if save.Dex[i].Info == nil {
+			save.Dex[i].Info = d.Info
+		}
+	}
+
+	if report.Added > 0 || report.Seen > 0 {
+		save.record(Operation{Kind: "import", After: added, Dex: snapshot, Absent: absent})
+	}
+	return report
+}
+
+func (save *SaveFile) findCatch(p Pokemon, matched map[int]bool) (Pokemon, bool) {
+	for _, existing := range save.Pokemon {
+		if !matched[existing.InstanceID] && sameCatch(existing, p) {
+			return existing, true
+		}
+	}
+	return Pokemon{}, false
+}
+
+func (save *SaveFile) findNickname(nickname string) (Pokemon, bool) {
+	for _, p := range save.Pokemon {
+		if p.Nickname != "" && strings.EqualFold(p.Nickname, nickname) {
+			return p, true
+		}
+	}
+	return Pokemon{}, false
+}
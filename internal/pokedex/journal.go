@@ -0,0 +1,97 @@
+package pokedex
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxJournal is how many operations are kept for undo.
+const maxJournal = 50
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Operation is a change to your collection, recorded in the save so it
+// can be undone. Undo removes the After Pokemon, puts the Before Pokemon
+// back, restores the caught state of the Dex entries and removes the
+// Absent species, which were not in the Dex before.
+type Operation struct {
+	Kind   string     `json:"kind"`
+	At     time.Time  `json:"at"`
+	Before []Pokemon  `json:"before,omitempty"`
+	After  []Pokemon  `json:"after,omitempty"`
+	Dex    []DexEntry `json:"dex,omitempty"`
+	Absent []string   `json:"absent,omitempty"`
+}
+
+func (op Operation) Describe() string {
+	list := op.After
+	if len(list) == 0 {
+		list = op.Before
+	}
+	names := make([]string, len(list))
+	for i, p := range list {
+		names[i] = fmt.Sprintf("%s #%d", p.Species, p.InstanceID)
+	}
+	return fmt.Sprintf("%s of %s", op.Kind, strings.Join(names, ", "))
+}
+
+// dexSnapshot records the Dex state of the species of list, so undo can
+// restore it, and which of them are not in the Dex yet.
+func (save *SaveFile) dexSnapshot(list []Pokemon) ([]DexEntry, []string) {
+	snapshot := []DexEntry{}
+	var absent []string
+	taken := make(map[string]bool)
+	for _, p := range list {
+		if taken[p.Species] {
+			continue
+		}
+		taken[p.Species] = true
+
+		i := save.findDex(p.Species)
+		if i < 0 {
+			absent = append(absent, p.Species)
+			continue
+		}
+		snapshot = append(snapshot, DexEntry{ID: p.SpeciesID, Name: p.Species, Caught: save.Dex[i].Caught})
+	}
+	return snapshot, absent
+}
+
+func (save *SaveFile) record(op Operation) {
+	op.At = time.Now()
+	save.Journal = append(save.Journal, op)
+	if len(save.Journal) > maxJournal {
+		save.Journal = save.Journal[len(save.Journal)-maxJournal:]
+	}
+}
+
+func (save *SaveFile) undo() (Operation, error) {
+	if len(save.Journal) == 0 {
+		return Operation{}, ErrNothingToUndo
+	}
+	op := save.Journal[len(save.Journal)-1]
+	save.Journal = save.Journal[:len(save.Journal)-1]
+
+	for _, p := range op.After {
+		save.remove(p.InstanceID)
+	}
+	for _, p := range op.Before {
+		if save.findPokemon(p.InstanceID) < 0 {
+			save.Pokemon = append(save.Pokemon, p)
+		}
+	}
+	for _, d := range op.Dex {
+		if i := save.findDex(d.Name); i >= 0 {
+			save.Dex[i].Caught = d.Caught
+		} else {
+			save.Dex = append(save.Dex, d)
+		}
+	}
+	save.Dex = slices.DeleteFunc(save.Dex, func(d DexEntry) bool {
+		return slices.Contains(op.Absent, d.Name)
+	})
+	return op, nil
+}
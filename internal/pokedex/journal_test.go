@@ -0,0 +1,87 @@
+package pokedex
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func testUndo(t *testing.T, store Store) {
+	if _, err := store.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected nothing to undo, got %v", err)
+	}
+
+	store.MarkSeen(DexEntry{ID: 25, Name: "pikachu"})
+	pikachu, _ := store.Add(Pokemon{SpeciesID: 25, Species: "pikachu"}, nil)
+	bulbasaur, _ := store.Add(Pokemon{SpeciesID: 1, Species: "bulbasaur"}, nil)
+
+	if _, err := store.Remove(pikachu.InstanceID); err != nil {
+		t.Fatal(err)
+	}
+
+	op, err := store.Undo()
+	if err != nil || op.Kind != "release" {
+		t.Fatalf("expected to undo the release, got %v (%v)", op, err)
+	}
+	if _, ok, _ := store.Get("pikachu"); !ok {
+		t.Errorf("expected released pikachu to be back")
+	}
+
+	op, err = store.Undo()
+	if err != nil || op.Kind != "catch" || op.After[0].InstanceID != bulbasaur.InstanceID {
+		t.Fatalf("expected to undo the bulbasaur catch, got %v (%v)", op, err)
+	}
+	if _, ok, _ := store.Get("bulbasaur"); ok {
+		t.Errorf("expected bulbasaur to be gone")
+	}
+	dex, _ := store.Dex()
+	if len(dex) != 1 || dex[0].Name != "pikachu" {
+		t.Errorf("expected bulbasaur to be gone from the Dex, got %v", dex)
+	}
+
+	if _, err := store.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Has("pikachu"); ok {
+		t.Errorf("expected pikachu to be back to seen")
+	}
+	if dex, _ := store.Dex(); len(dex) != 1 || dex[0].Caught {
+		t.Errorf("expected undo to keep seen species, got %v", dex)
+	}
+
+	next, _ := store.Add(Pokemon{SpeciesID: 25, Species: "pikachu"}, nil)
+	if next.InstanceID != 3 {
+		t.Errorf("expected undone instance ids to not be reused, got %d", next.InstanceID)
+	}
+}
+
+func TestUndoImportRestoresDex(t *testing.T) {
+	store := NewMemoryStore()
+	store.MarkSeen(DexEntry{ID: 25, Name: "pikachu"})
+	before, _ := store.Dex()
+
+	_, err := store.Import([]DexEntry{{ID: 7, Name: "squirtle"}}, []Pokemon{
+		{SpeciesID: 1, Species: "bulbasaur"},
+		{SpeciesID: 25, Species: "pikachu"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	after, _ := store.Dex()
+	if !slices.Equal(after, before) {
+		t.Errorf("dex does not match. Actual: %v - vs - Expected: %v", after, before)
+	}
+}
+
+func TestMemoryStoreUndo(t *testing.T) {
+	testUndo(t, NewMemoryStore())
+}
+
+func TestFileStoreUndo(t *testing.T) {
+	testUndo(t, NewFileStore(filepath.Join(t.TempDir(), "caught.json")))
+}
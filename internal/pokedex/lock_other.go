@@ -0,0 +1,9 @@
+//go:build !unix
+
+package pokedex
+
+// LockFile is a no-op where flock is not available; writes are still
+// atomic, but two running REPLs may lose each other's changes.
+func LockFile(path string) (func(), error) {
+	return func() {}, nil
+}
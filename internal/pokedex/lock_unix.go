@@ -0,0 +1,28 @@
+//go:build unix
+
+package pokedex
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// LockFile takes an exclusive advisory lock on path+".lock", blocking
+// until any other Pokedex process releases it.
+func LockFile(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,44 @@
+package pokedex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxNicknameLength matches the limit of the games since Gen 6, counted
+// in characters rather than bytes.
+const MaxNicknameLength = 12
+
+// ValidateNickname checks that a nickname fits, can't be mistaken for an
+// instance ID and is a single word, as commands take it as one argument.
+func ValidateNickname(nickname string) error {
+	if !utf8.ValidString(nickname) {
+		return errors.New("nickname is not valid text")
+	}
+	if n := utf8.RuneCountInString(nickname); n > MaxNicknameLength {
+		return fmt.Errorf("nickname is %d characters, the limit is %d", n, MaxNicknameLength)
+	}
+	if strings.HasPrefix(nickname, "#") || strings.HasPrefix(nickname, "--") {
+		return errors.New("nickname can't start with # or --")
+	}
+
+	digits := true
+	for _, r := range nickname {
+		if unicode.IsSpace(r) {
+			return errors.New("nickname can't contain spaces")
+		}
+		if !unicode.IsPrint(r) {
+			return errors.New("nickname can't contain control characters")
+		}
+		if !unicode.IsDigit(r) {
+			digits = false
+		}
+	}
+	if digits {
+		return errors.New("nickname can't be only digits")
+	}
+	return nil
+}
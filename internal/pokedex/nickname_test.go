@@ -0,0 +1,86 @@
+package pokedex
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateNickname(t *testing.T) {
+	cases := []struct {
+		nickname string
+		valid    bool
+	}{
+		{nickname: "Sparky", valid: true},
+		{nickname: "Mr.Bubbles", valid: true},
+		{nickname: "Mr. Bubbles", valid: false},
+		{nickname: "ピカチュウ", valid: true},
+		{nickname: "TwelveCharss", valid: true},
+		{nickname: "ThirteenChars", valid: false},
+		{nickname: "123", valid: false},
+		{nickname: "#3", valid: false},
+		{nickname: "--clear", valid: false},
+		{nickname: "tab\there", valid: false},
+	}
+
+	for _, c := range cases {
+		err := ValidateNickname(c.nickname)
+		if (err == nil) != c.valid {
+			t.Errorf("%q: expected valid %v, got %v", c.nickname, c.valid, err)
+		}
+	}
+}
+
+func testNicknames(t *testing.T, store Store) {
+	first, _ := store.Add(Pokemon{SpeciesID: 25, Species: "pikachu"}, nil)
+	second, _ := store.Add(Pokemon{SpeciesID: 25, Species: "pikachu"}, nil)
+	if err := store.MarkSeen(DexEntry{ID: 1, Name: "bulbasaur"}); err != nil {
+		t.Fatal(err)
+	}
+
+	named, err := store.SetNickname(first.InstanceID, "Sparky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if named.DisplayName() != "Sparky (pikachu)" {
+		t.Errorf("display name does not match. Actual: %s - vs - Expected: %s", named.DisplayName(), "Sparky (pikachu)")
+	}
+
+	p, ok, err := store.Get("sparky")
+	if err != nil || !ok || p.InstanceID != first.InstanceID {
+		t.Errorf("expected to get pikachu #%d by nickname, got %v %v (%v)", first.InstanceID, p, ok, err)
+	}
+	if _, err := store.SetNickname(second.InstanceID, "SPARKY"); err == nil {
+		t.Errorf("expected an error reusing a nickname")
+	}
+	if _, err := store.SetNickname(first.InstanceID, "SPARKY"); err != nil {
+		t.Errorf("expected to recase a Pokemon's own nickname, got %v", err)
+	}
+	if _, err := store.SetNickname(first.InstanceID, "Bulbasaur"); err == nil {
+		t.Errorf("expected an error naming a Pokemon after a species")
+	}
+	if _, err := store.SetNickname(99, "Ghost"); err == nil {
+		t.Errorf("expected an error naming a missing Pokemon")
+	}
+
+	op, err := store.Undo()
+	if err != nil || op.Kind != "nickname" {
+		t.Fatalf("expected to undo the nickname, got %v (%v)", op, err)
+	}
+	p, _, _ = store.Get("#1")
+	if p.Nickname != "Sparky" {
+		t.Errorf("nickname not restored. Actual: %s - vs - Expected: %s", p.Nickname, "Sparky")
+	}
+
+	cleared, err := store.SetNickname(first.InstanceID, "")
+	if err != nil || cleared.DisplayName() != "pikachu" {
+		t.Errorf("expected nickname to be cleared, got %v (%v)", cleared, err)
+	}
+}
+
+func TestMemoryStoreNicknames(t *testing.T) {
+	testNicknames(t, NewMemoryStore())
+}
+
+func TestFileStoreNicknames(t *testing.T) {
+	testNicknames(t, NewFileStore(filepath.Join(t.TempDir(), "caught.json")))
+}
@@ -0,0 +1,81 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StatTable holds the base stats of every species by name, synced from
+// pokeapi so stats can be ranked against all Pokemon.
+type StatTable map[string]BaseStats
+
+// StatTablePath is where the synced stat table is kept. Like sprites it
+// is shared by every profile.
+func StatTablePath() string {
+	return filepath.Join(cacheDir, "base-stats.json")
+}
+
+// LoadStatTable reads the synced stat table, or returns nil if stats
+// have not been synced yet.
+func LoadStatTable() (StatTable, error) {
+	data, err := os.ReadFile(StatTablePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read synced stats: %w", err)
+	}
+
+	var table StatTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse synced stats: %w", err)
+	}
+	return table, nil
+}
+
+func SaveStatTable(table StatTable) error {
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode synced stats: %w", err)
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := WriteFileAtomic(StatTablePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to save synced stats: %w", err)
+	}
+	return nil
+}
+
+// Has reports whether name is a species in the table, ignoring case.
+func (t StatTable) Has(name string) bool {
+	_, ok := t[strings.ToLower(name)]
+	return ok
+}
+
+// Percentile ranks a value of a stat, as named by BaseStats.Stat, against
+// every species in the table: the percentage of species below it, with
+// ties counting half.
+func (t StatTable) Percentile(stat string, value int) (int, bool) {
+	if len(t) == 0 {
+		return 0, false
+	}
+
+	var below, equal int
+	for _, stats := range t {
+		other, ok := stats.Stat(stat)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case other < value:
+			below++
+		case other == value:
+			equal++
+		}
+	}
+	return (200*below + 100*equal + len(t)) / (2 * len(t)), true
+}
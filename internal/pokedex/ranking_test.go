@@ -0,0 +1,60 @@
+package pokedex
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStatTablePercentile(t *testing.T) {
+	table := StatTable{
+		"caterpie":  {HP: 45, Speed: 45},
+		"pikachu":   {HP: 35, Speed: 90},
+		"snorlax":   {HP: 160, Speed: 30},
+		"blissey":   {HP: 255, Speed: 55},
+		"bulbasaur": {HP: 45, Speed: 45},
+	}
+
+	cases := []struct {
+		stat     string
+		value    int
+		expected int
+	}{
+		{stat: "hp", value: 255, expected: 90},
+		{stat: "hp", value: 45, expected: 40},
+		{stat: "hp", value: 10, expected: 0},
+		{stat: "speed", value: 200, expected: 100},
+		{stat: "bst", value: 90, expected: 20},
+	}
+	for _, c := range cases {
+		actual, ok := table.Percentile(c.stat, c.value)
+		if !ok || actual != c.expected {
+			t.Errorf("%s %d: Actual: %d - vs - Expected: %d", c.stat, c.value, actual, c.expected)
+		}
+	}
+
+	if _, ok := table.Percentile("luck", 10); ok {
+		t.Errorf("expected no percentile for an unknown stat")
+	}
+	if _, ok := StatTable(nil).Percentile("hp", 10); ok {
+		t.Errorf("expected no percentile without synced stats")
+	}
+}
+
+func TestStatTableRoundTrip(t *testing.T) {
+	SetCacheDir(filepath.Join(t.TempDir(), "cache"))
+	defer SetCacheDir(".cache")
+
+	if table, err := LoadStatTable(); err != nil || table != nil {
+		t.Fatalf("expected no table before syncing, got %v (%v)", table, err)
+	}
+	if err := SaveStatTable(StatTable{"pikachu": {HP: 35}}); err != nil {
+		t.Fatal(err)
+	}
+	table, err := LoadStatTable()
+	if err != nil || table["pikachu"].HP != 35 {
+		t.Errorf("expected the synced table back, got %v (%v)", table, err)
+	}
+	if !table.Has("Pikachu") || table.Has("sparky") {
+		t.Errorf("expected only pikachu to be a species in %v", table)
+	}
+}
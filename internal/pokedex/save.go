@@ -0,0 +1,344 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// SaveFile is the document stored in caught.json. Dex tracks which
+// species you have completed, Pokemon holds every individual you own.
+type SaveFile struct {
+	Version        int         `json:"version"`
+	Trainer        Trainer     `json:"trainer"`
+	Dex            []DexEntry  `json:"dex"`
+	Pokemon        []Pokemon   `json:"pokemon"`
+	NextInstanceID int         `json:"next_instance_id"`
+	Journal        []Operation `json:"journal,omitempty"`
+}
+
+type Trainer struct {
+	Name string `json:"name"`
+}
+
+// A migration upgrades a raw save document by exactly one version.
+// migrations[i] turns a version i save into a version i+1 save.
+type migration func(data []byte) ([]byte, error)
+
+var migrations = []migration{
+	migrateV0ToV1,
+	migrateV1ToV2,
+	migrateV2ToV3,
+	migrateV3ToV4,
+	migrateV4ToV5,
+	migrateV5ToV6,
+	migrateV6ToV7,
+	migrateV7ToV8,
+	migrateV8ToV9,
+}
+
+// CurrentVersion is the save file version written by this Pokedex.
+var CurrentVersion = len(migrations)
+
+func newSaveFile() SaveFile {
+	return SaveFile{
+		Version:        CurrentVersion,
+		Dex:            []DexEntry{},
+		Pokemon:        []Pokemon{},
+		NextInstanceID: 1,
+	}
+}
+
+// saveVersion reports the version of a raw save. The original caught.json
+// was a bare map of ID to name with no version field, which is version 0.
+func saveVersion(data []byte) (int, error) {
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+
+	raw, ok := header["version"]
+	if !ok {
+		return 0, nil
+	}
+
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("invalid save version: %w", err)
+	}
+	return version, nil
+}
+
+// migrateSave upgrades a raw save to CurrentVersion and returns the
+// version it started from.
+func migrateSave(data []byte) ([]byte, int, error) {
+	from, err := saveVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > CurrentVersion {
+		return nil, from, fmt.Errorf("save version %d is newer than this Pokedex supports (%d)", from, CurrentVersion)
+	}
+
+	for v := from; v < CurrentVersion; v++ {
+		data, err = migrations[v](data)
+		if err != nil {
+			return nil, from, fmt.Errorf("failed to migrate save from version %d: %w", v, err)
+		}
+	}
+	return data, from, nil
+}
+
+func decodeSave(data []byte) (SaveFile, int, error) {
+	migrated, from, err := migrateSave(data)
+	if err != nil {
+		return SaveFile{}, from, err
+	}
+
+	var save SaveFile
+	if err := json.Unmarshal(migrated, &save); err != nil {
+		return SaveFile{}, from, err
+	}
+	if save.Dex == nil {
+		save.Dex = []DexEntry{}
+	}
+	if save.Pokemon == nil {
+		save.Pokemon = []Pokemon{}
+	}
+	if save.NextInstanceID < 1 {
+		save.NextInstanceID = 1
+	}
+	return save, from, nil
+}
+
+// SaveSpecies lists the species named in a raw save of any version.
+func SaveSpecies(data []byte) ([]string, error) {
+	save, _, err := decodeSave(data)
+	if err != nil {
+		return nil, err
+	}
+	species := make([]string, 0, len(save.Dex))
+	for _, d := range save.Dex {
+		species = append(species, d.Name)
+	}
+	return species, nil
+}
+
+func encodeSave(save SaveFile) ([]byte, error) {
+	save.Version = CurrentVersion
+	sortDex(save.Dex)
+	sortPokemon(save.Pokemon)
+	return json.MarshalIndent(save, "", "  ")
+}
+
+func migrateV0ToV1(data []byte) ([]byte, error) {
+	caught := make(map[string]string)
+	if err := json.Unmarshal(data, &caught); err != nil {
+		return nil, err
+	}
+
+	// migrations use their own copies of the types so later changes to
+	// SaveFile and Entry don't alter what older steps produce
+	type entryV1 struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type saveV1 struct {
+		Version int               `json:"version"`
+		Trainer map[string]string `json:"trainer"`
+		Entries []entryV1         `json:"entries"`
+	}
+
+	save := saveV1{
+		Version: 1,
+		Trainer: map[string]string{"name": ""},
+		Entries: make([]entryV1, 0, len(caught)),
+	}
+	for key, name := range caught {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pokedex id %q", key)
+		}
+		save.Entries = append(save.Entries, entryV1{ID: id, Name: name})
+	}
+	sort.Slice(save.Entries, func(i, j int) bool {
+		return save.Entries[i].ID < save.Entries[j].ID
+	})
+
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV1ToV2 only bumps the version. Version 2 adds optional catch
+// details to entries, and the bump keeps older builds, which would drop
+// those details on their next write, from opening the save.
+func migrateV1ToV2(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	save["version"] = json.RawMessage("2")
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV2ToV3 splits entries into the species Dex and individual
+// Pokemon, numbering the existing catches as instances 1 to n.
+func migrateV2ToV3(data []byte) ([]byte, error) {
+	type entryV2 struct {
+		ID       int             `json:"id"`
+		Name     string          `json:"name"`
+		CaughtAt json.RawMessage `json:"caught_at,omitempty"`
+		Location string          `json:"location,omitempty"`
+		Attempts int             `json:"attempts,omitempty"`
+		Level    int             `json:"level,omitempty"`
+		Ball     string          `json:"ball,omitempty"`
+		Shiny    bool            `json:"shiny,omitempty"`
+	}
+	type saveV2 struct {
+		Trainer json.RawMessage `json:"trainer"`
+		Entries []entryV2       `json:"entries"`
+	}
+	type dexV3 struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type pokemonV3 struct {
+		InstanceID int             `json:"instance_id"`
+		SpeciesID  int             `json:"species_id"`
+		Species    string          `json:"species"`
+		CaughtAt   json.RawMessage `json:"caught_at,omitempty"`
+		Location   string          `json:"location,omitempty"`
+		Attempts   int             `json:"attempts,omitempty"`
+		Level      int             `json:"level,omitempty"`
+		Ball       string          `json:"ball,omitempty"`
+		Shiny      bool            `json:"shiny,omitempty"`
+	}
+	type saveV3 struct {
+		Version        int             `json:"version"`
+		Trainer        json.RawMessage `json:"trainer"`
+		Dex            []dexV3         `json:"dex"`
+		Pokemon        []pokemonV3     `json:"pokemon"`
+		NextInstanceID int             `json:"next_instance_id"`
+	}
+
+	var old saveV2
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+	sort.Slice(old.Entries, func(i, j int) bool {
+		return old.Entries[i].ID < old.Entries[j].ID
+	})
+
+	save := saveV3{
+		Version: 3,
+		Trainer: old.Trainer,
+		Dex:     make([]dexV3, 0, len(old.Entries)),
+		Pokemon: make([]pokemonV3, 0, len(old.Entries)),
+	}
+	for i, e := range old.Entries {
+		save.Dex = append(save.Dex, dexV3{ID: e.ID, Name: e.Name})
+		save.Pokemon = append(save.Pokemon, pokemonV3{
+			InstanceID: i + 1,
+			SpeciesID:  e.ID,
+			Species:    e.Name,
+			CaughtAt:   e.CaughtAt,
+			Location:   e.Location,
+			Attempts:   e.Attempts,
+			Level:      e.Level,
+			Ball:       e.Ball,
+			Shiny:      e.Shiny,
+		})
+	}
+	save.NextInstanceID = len(old.Entries) + 1
+
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV3ToV4 adds seen tracking to the Dex. Every species in a
+// version 3 Dex had been caught.
+func migrateV3ToV4(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	var dex []map[string]json.RawMessage
+	if raw, ok := save["dex"]; ok {
+		if err := json.Unmarshal(raw, &dex); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range dex {
+		d["caught"] = json.RawMessage("true")
+	}
+
+	raw, err := json.Marshal(dex)
+	if err != nil {
+		return nil, err
+	}
+	save["dex"] = raw
+	save["version"] = json.RawMessage("4")
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV4ToV5 only bumps the version. Version 5 adds optional species
+// data to Dex entries, which is filled in the next time it is needed.
+func migrateV4ToV5(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	save["version"] = json.RawMessage("5")
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV5ToV6 only bumps the version. Version 6 adds the undo journal,
+// which starts out empty.
+func migrateV5ToV6(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	save["version"] = json.RawMessage("6")
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV6ToV7 only bumps the version. Version 7 adds optional nicknames
+// to Pokemon.
+func migrateV6ToV7(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	save["version"] = json.RawMessage("7")
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV7ToV8 only bumps the version. Version 8 adds the optional
+// ability, item, EVs, IVs and moves of Pokemon imported from a team
+// paste.
+func migrateV7ToV8(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	save["version"] = json.RawMessage("8")
+	return json.MarshalIndent(save, "", "  ")
+}
+
+// migrateV8ToV9 only bumps the version. Version 9 adds the optional OT
+// and DVs of Pokemon imported from a game save.
+func migrateV8ToV9(data []byte) ([]byte, error) {
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	save["version"] = json.RawMessage("9")
+	return json.MarshalIndent(save, "", "  ")
+}
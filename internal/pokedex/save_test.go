@@ -0,0 +1,90 @@
+package pokedex
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func goldenPath(version int) string {
+	return filepath.Join("testdata", "migrations", fmt.Sprintf("v%d.json", version))
+}
+
+// Each migration step turns testdata/migrations/v<n>.json into
+// v<n+1>.json. Run with -update to regenerate the golden files.
+func TestMigrationSteps(t *testing.T) {
+	for v := 0; v < CurrentVersion; v++ {
+		t.Run(fmt.Sprintf("v%d to v%d", v, v+1), func(t *testing.T) {
+			input, err := os.ReadFile(goldenPath(v))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := migrations[v](input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual = append(actual, '\n')
+
+			if *update {
+				if err := os.WriteFile(goldenPath(v+1), actual, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(goldenPath(v + 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != string(expected) {
+				t.Errorf("migrated save does not match golden file.\nActual:\n%s\nExpected:\n%s", actual, expected)
+			}
+
+			version, err := saveVersion(actual)
+			if err != nil || version != v+1 {
+				t.Errorf("expected migrated save to be version %d, got %d (%v)", v+1, version, err)
+			}
+		})
+	}
+}
+
+func TestMigrateSaveRejectsNewerVersion(t *testing.T) {
+	data := []byte(fmt.Sprintf(`{"version": %d, "entries": []}`, CurrentVersion+1))
+	if _, _, err := decodeSave(data); err == nil {
+		t.Errorf("expected an error for a save from a newer version")
+	}
+}
+
+func TestFileStoreUpgrade(t *testing.T) {
+	legacy, err := os.ReadFile(goldenPath(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "caught.json")
+	if err := os.WriteFile(path, legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewFileStore(path)
+	from, err := store.Upgrade()
+	if err != nil || from != 0 {
+		t.Fatalf("expected upgrade from version 0, got %d (%v)", from, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if version, _ := saveVersion(data); version != CurrentVersion {
+		t.Errorf("expected save to be rewritten as version %d, got %d", CurrentVersion, version)
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); string(backup) != string(legacy) {
+		t.Errorf("expected the legacy save to be kept as a backup")
+	}
+
+	from, err = store.Upgrade()
+	if err != nil || from != CurrentVersion {
+		t.Errorf("expected no upgrade for a current save, got %d (%v)", from, err)
+	}
+}
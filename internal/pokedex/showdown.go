@@ -0,0 +1,273 @@
+package pokedex
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	showdownLevel = 100
+	maxIV         = 31
+	maxEV         = 252
+	maxTotalEVs   = 510
+)
+
+// showdownStats are the stat abbreviations used on EVs and IVs lines, in
+// the order Showdown writes them.
+var showdownStats = []struct {
+	label string
+	field func(s *StatSpread) *int
+}{
+	{"HP", func(s *StatSpread) *int { return &s.HP }},
+	{"Atk", func(s *StatSpread) *int { return &s.Attack }},
+	{"Def", func(s *StatSpread) *int { return &s.Defense }},
+	{"SpA", func(s *StatSpread) *int { return &s.SpAtk }},
+	{"SpD", func(s *StatSpread) *int { return &s.SpDef }},
+	{"Spe", func(s *StatSpread) *int { return &s.Speed }},
+}
+
+// ShowdownID turns a name as written in a paste, like "Volt Tackle" or
+// "Farfetch'd", into its pokeapi identifier.
+func ShowdownID(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// showdownName turns a pokeapi identifier back into a display name.
+func showdownName(id string, sep string) string {
+	words := strings.Split(id, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, sep)
+}
+
+// FormatShowdown writes Pokemon as a Showdown team paste, leaving out
+// whatever is not known about them.
+func FormatShowdown(pokemon []Pokemon) string {
+	sets := make([]string, 0, len(pokemon))
+	for _, p := range pokemon {
+		var b strings.Builder
+
+		species := showdownName(p.Species, "-")
+		if p.Nickname != "" {
+			fmt.Fprintf(&b, "%s (%s)", p.Nickname, species)
+		} else {
+			b.WriteString(species)
+		}
+		if p.Item != "" {
+			fmt.Fprintf(&b, " @ %s", showdownName(p.Item, " "))
+		}
+		b.WriteString("\n")
+
+		if p.Ability != "" {
+			fmt.Fprintf(&b, "Ability: %s\n", showdownName(p.Ability, " "))
+		}
+		if p.Level != 0 && p.Level != showdownLevel {
+			fmt.Fprintf(&b, "Level: %d\n", p.Level)
+		}
+		if p.Shiny {
+			b.WriteString("Shiny: Yes\n")
+		}
+		if evs := formatSpread(p.EVs, 0); evs != "" {
+			fmt.Fprintf(&b, "EVs: %s\n", evs)
+		}
+		if p.Nature != "" {
+			fmt.Fprintf(&b, "%s Nature\n", showdownName(p.Nature, " "))
+		}
+		if p.IVs != (StatSpread{}) {
+			if ivs := formatSpread(p.IVs, maxIV); ivs != "" {
+				fmt.Fprintf(&b, "IVs: %s\n", ivs)
+			}
+		}
+		for _, move := range p.Moves {
+			if move != "" {
+				fmt.Fprintf(&b, "- %s\n", showdownName(move, " "))
+			}
+		}
+		sets = append(sets, b.String())
+	}
+	return strings.Join(sets, "\n")
+}
+
+// formatSpread lists the stats that differ from the default value, like
+// "252 Atk / 4 SpD / 252 Spe".
+func formatSpread(spread StatSpread, def int) string {
+	parts := []string{}
+	for _, stat := range showdownStats {
+		if value := *stat.field(&spread); value != def {
+			parts = append(parts, fmt.Sprintf("%d %s", value, stat.label))
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// ParseShowdown reads a Showdown team paste. Names are converted to
+// pokeapi identifiers but not checked against the API, see
+// ValidateShowdown. Unknown "Key: value" lines such as Tera Type are
+// skipped.
+func ParseShowdown(paste string) ([]Pokemon, error) {
+	pokemon := []Pokemon{}
+	var current *Pokemon
+	moves := 0
+
+	lines := strings.Split(strings.ReplaceAll(paste, "\r\n", "\n"), "\n")
+	for n, line := range lines {
+		line = strings.TrimSpace(line)
+		lineErr := func(format string, args ...any) error {
+			return fmt.Errorf("line %d: %s", n+1, fmt.Sprintf(format, args...))
+		}
+
+		if line == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			p, err := parseShowdownHeader(line)
+			if err != nil {
+				return nil, lineErr("%v", err)
+			}
+			pokemon = append(pokemon, p)
+			current = &pokemon[len(pokemon)-1]
+			moves = 0
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "-"):
+			if moves == len(current.Moves) {
+				return nil, lineErr("a Pokemon can't know more than %d moves", len(current.Moves))
+			}
+			current.Moves[moves] = ShowdownID(strings.TrimPrefix(line, "-"))
+			moves++
+		case strings.HasSuffix(line, " Nature"):
+			nature := ShowdownID(strings.TrimSuffix(line, " Nature"))
+			if !slices.Contains(Natures, nature) {
+				return nil, lineErr("unknown nature %q", nature)
+			}
+			current.Nature = nature
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return nil, lineErr("can't read %q", line)
+			}
+			value = strings.TrimSpace(value)
+
+			switch key {
+			case "Ability":
+				current.Ability = ShowdownID(value)
+			case "Level":
+				level, err := strconv.Atoi(value)
+				if err != nil || level < 1 || level > 100 {
+					return nil, lineErr("level must be between 1 and 100")
+				}
+				current.Level = level
+			case "Shiny":
+				current.Shiny = value == "Yes"
+			case "EVs":
+				evs, err := parseSpread(value, 0, maxEV)
+				if err != nil {
+					return nil, lineErr("%v", err)
+				}
+				if total := BaseStats(evs).Total(); total > maxTotalEVs {
+					return nil, lineErr("EVs add up to %d, the limit is %d", total, maxTotalEVs)
+				}
+				current.EVs = evs
+			case "IVs":
+				ivs, err := parseSpread(value, maxIV, maxIV)
+				if err != nil {
+					return nil, lineErr("%v", err)
+				}
+				current.IVs = ivs
+			}
+		}
+	}
+	return pokemon, nil
+}
+
+// parseShowdownHeader reads the first line of a set, one of
+//
+//	Species
+//	Nickname (Species) (M) @ Item
+func parseShowdownHeader(line string) (Pokemon, error) {
+	p := Pokemon{Level: showdownLevel}
+
+	name, item, hasItem := strings.Cut(line, " @ ")
+	if hasItem {
+		p.Item = ShowdownID(item)
+	}
+	name = strings.TrimSpace(name)
+	name = strings.TrimSuffix(strings.TrimSuffix(name, " (M)"), " (F)")
+
+	if open := strings.LastIndex(name, " ("); open >= 0 && strings.HasSuffix(name, ")") {
+		p.Nickname = strings.TrimSpace(name[:open])
+		name = name[open+2 : len(name)-1]
+	}
+	p.Species = ShowdownID(name)
+	if p.Species == "" {
+		return Pokemon{}, fmt.Errorf("missing species in %q", line)
+	}
+	return p, nil
+}
+
+func parseSpread(value string, def, limit int) (StatSpread, error) {
+	var spread StatSpread
+	for _, stat := range showdownStats {
+		*stat.field(&spread) = def
+	}
+
+	for _, part := range strings.Split(value, "/") {
+		amount, label, ok := strings.Cut(strings.TrimSpace(part), " ")
+		n, err := strconv.Atoi(amount)
+		if !ok || err != nil {
+			return StatSpread{}, fmt.Errorf("can't read %q, expected something like 252 Atk", part)
+		}
+		if n < 0 || n > limit {
+			return StatSpread{}, fmt.Errorf("%s must be between 0 and %d", label, limit)
+		}
+
+		found := false
+		for _, stat := range showdownStats {
+			if strings.EqualFold(stat.label, label) {
+				*stat.field(&spread) = n
+				found = true
+			}
+		}
+		if !found {
+			return StatSpread{}, fmt.Errorf("unknown stat %q", label)
+		}
+	}
+	return spread, nil
+}
+
+// ValidateShowdown checks a parsed set against the raw pokeapi data of
+// its species: the ability must be one the species can have and every
+// move one it can learn.
+func ValidateShowdown(p Pokemon, pokemonDataRaw []byte) error {
+	abilities, learnable, err := ParseLearnset(pokemonDataRaw)
+	if err != nil {
+		return err
+	}
+
+	if p.Ability != "" && !slices.Contains(abilities, p.Ability) {
+		return fmt.Errorf("%s can't have the ability %s", p.Species, p.Ability)
+	}
+	for _, move := range p.Moves {
+		if move != "" && !slices.Contains(learnable, move) {
+			return fmt.Errorf("%s can't learn %s", p.Species, move)
+		}
+	}
+	return nil
+}
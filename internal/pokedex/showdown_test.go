@@ -0,0 +1,148 @@
+package pokedex
+
+import (
+	"strings"
+	"testing"
+)
+
+const pikachuPaste = `Sparky (Pikachu) (M) @ Light Ball
+Ability: Static
+Level: 50
+Shiny: Yes
+Tera Type: Electric
+EVs: 252 Atk / 4 SpD / 252 Spe
+Jolly Nature
+IVs: 0 SpA
+- Volt Tackle
+- Iron Tail
+
+Bulbasaur
+- Vine Whip
+`
+
+func TestParseShowdown(t *testing.T) {
+	team, err := ParseShowdown(pikachuPaste)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(team) != 2 {
+		t.Fatalf("len of actual not the same as expected. Actual: %d - vs - Expected: %d", len(team), 2)
+	}
+
+	expected := Pokemon{
+		Species:  "pikachu",
+		Nickname: "Sparky",
+		Item:     "light-ball",
+		Ability:  "static",
+		Level:    50,
+		Shiny:    true,
+		Nature:   "jolly",
+		EVs:      StatSpread{Attack: 252, SpDef: 4, Speed: 252},
+		IVs:      StatSpread{HP: 31, Attack: 31, Defense: 31, SpAtk: 0, SpDef: 31, Speed: 31},
+		Moves:    [4]string{"volt-tackle", "iron-tail"},
+	}
+	if team[0] != expected {
+		t.Errorf("pokemon does not match. Actual: %+v - vs - Expected: %+v", team[0], expected)
+	}
+	if team[1].Species != "bulbasaur" || team[1].Level != 100 {
+		t.Errorf("expected a level 100 bulbasaur, got %+v", team[1])
+	}
+}
+
+func TestFormatShowdownRoundTrip(t *testing.T) {
+	team, err := ParseShowdown(pikachuPaste)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := ParseShowdown(FormatShowdown(team))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range team {
+		if again[i] != team[i] {
+			t.Errorf("pokemon does not match. Actual: %+v - vs - Expected: %+v", again[i], team[i])
+		}
+	}
+
+	caught := FormatShowdown([]Pokemon{{Species: "mr-mime", Level: 12}})
+	if caught != "Mr-Mime\nLevel: 12\n" {
+		t.Errorf("expected unknown details to be left out, got %q", caught)
+	}
+}
+
+func TestParseShowdownErrors(t *testing.T) {
+	cases := []struct {
+		paste string
+		err   string
+	}{
+		{paste: "Pikachu\n- A\n- B\n- C\n- D\n- E", err: "line 6"},
+		{paste: "Pikachu\nEVs: 252 Atk / 252 Def / 252 Spe", err: "add up to 756"},
+		{paste: "Pikachu\nIVs: 32 HP", err: "between 0 and 31"},
+		{paste: "Pikachu\nGrumpy Nature", err: "unknown nature"},
+		{paste: "Pikachu\nLevel: 101", err: "level"},
+		{paste: "Pikachu\nwhat is this", err: "line 2"},
+	}
+
+	for _, c := range cases {
+		_, err := ParseShowdown(c.paste)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("expected an error containing %q for %q, got %v", c.err, c.paste, err)
+		}
+	}
+}
+
+func TestValidateShowdown(t *testing.T) {
+	raw := []byte(`{
+		"abilities": [{"ability": {"name": "static"}}, {"ability": {"name": "lightning-rod"}}],
+		"moves": [{"move": {"name": "volt-tackle"}}, {"move": {"name": "iron-tail"}}]
+	}`)
+
+	valid := Pokemon{Species: "pikachu", Ability: "static", Moves: [4]string{"volt-tackle"}}
+	if err := ValidateShowdown(valid, raw); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	wrongAbility := Pokemon{Species: "pikachu", Ability: "overgrow"}
+	if err := ValidateShowdown(wrongAbility, raw); err == nil {
+		t.Errorf("expected an error for an ability pikachu can't have")
+	}
+
+	wrongMove := Pokemon{Species: "pikachu", Moves: [4]string{"iron-tail", "surf"}}
+	if err := ValidateShowdown(wrongMove, raw); err == nil {
+		t.Errorf("expected an error for a move pikachu can't learn")
+	}
+}
+
+func TestImportShowdownSets(t *testing.T) {
+	team, err := ParseShowdown(`Garchomp @ Choice Scarf
+Ability: Rough Skin
+EVs: 252 Atk / 4 SpD / 252 Spe
+Jolly Nature
+- Outrage
+- Earthquake
+
+Garchomp @ Rocky Helmet
+Ability: Rough Skin
+EVs: 252 HP / 252 Def / 4 Spe
+Impish Nature
+- Stealth Rock
+- Earthquake
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range team {
+		team[i].SpeciesID = 445
+	}
+	store := NewMemoryStore()
+	report, err := store.Import(nil, team)
+	if err != nil || report.Added != 2 || report.Duplicates != 0 {
+		t.Fatalf("expected both sets to be added, got %+v (%v)", report, err)
+	}
+	report, err = store.Import(nil, team)
+	if err != nil || report.Added != 0 || report.Duplicates != 2 {
+		t.Errorf("expected importing the paste twice to add nothing, got %+v (%v)", report, err)
+	}
+}
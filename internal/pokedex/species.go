@@ -0,0 +1,148 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type BaseStats struct {
+	HP      int `json:"hp"`
+	Attack  int `json:"attack"`
+	Defense int `json:"defense"`
+	SpAtk   int `json:"sp_atk"`
+	SpDef   int `json:"sp_def"`
+	Speed   int `json:"speed"`
+}
+
+func (s BaseStats) Total() int {
+	return s.HP + s.Attack + s.Defense + s.SpAtk + s.SpDef + s.Speed
+}
+
+// StatSpread holds a value per stat, such as the EVs or IVs of a
+// Pokemon.
+type StatSpread BaseStats
+
+// StatNames lists the names Stat accepts, in card order.
+var StatNames = []string{"hp", "attack", "defense", "sp-atk", "sp-def", "speed", "bst"}
+
+// Stat looks up a base stat by name, where "bst" is the base stat total.
+func (s BaseStats) Stat(name string) (int, bool) {
+	switch name {
+	case "hp":
+		return s.HP, true
+	case "attack":
+		return s.Attack, true
+	case "defense":
+		return s.Defense, true
+	case "sp-atk", "special-attack":
+		return s.SpAtk, true
+	case "sp-def", "special-defense":
+		return s.SpDef, true
+	case "speed":
+		return s.Speed, true
+	case "bst", "total":
+		return s.Total(), true
+	}
+	return 0, false
+}
+
+// SpeciesInfo is the species data kept in the Dex so listings can sort
+// and filter without going back to the API.
+type SpeciesInfo struct {
+	Types  []string  `json:"types"`
+	Stats  BaseStats `json:"stats"`
+	Height float64   `json:"height"`
+	Weight float64   `json:"weight"`
+	Sprite string    `json:"sprite,omitempty"`
+}
+
+func (i SpeciesInfo) HasType(t string) bool {
+	for _, own := range i.Types {
+		if own == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSpeciesInfo reads species data from a raw pokeapi pokemon
+// response. Height and weight are converted to metres and kilograms.
+func ParseSpeciesInfo(pokemonDataRaw []byte) (SpeciesInfo, error) {
+	var data struct {
+		Height float64 `json:"height"`
+		Weight float64 `json:"weight"`
+		Stats  []struct {
+			BaseStat int `json:"base_stat"`
+			Stat     struct {
+				Name string `json:"name"`
+			} `json:"stat"`
+		} `json:"stats"`
+		Types []struct {
+			Slot int `json:"slot"`
+			Type struct {
+				Name string `json:"name"`
+			} `json:"type"`
+		} `json:"types"`
+		Sprites struct {
+			FrontDefault string `json:"front_default"`
+		} `json:"sprites"`
+	}
+	if err := json.Unmarshal(pokemonDataRaw, &data); err != nil {
+		return SpeciesInfo{}, fmt.Errorf("failed to parse pokemon data: %w", err)
+	}
+
+	info := SpeciesInfo{
+		Types:  make([]string, 0, len(data.Types)),
+		Height: data.Height / 10.0,
+		Weight: data.Weight / 10.0,
+		Sprite: data.Sprites.FrontDefault,
+	}
+	for _, t := range data.Types {
+		info.Types = append(info.Types, t.Type.Name)
+	}
+	for _, s := range data.Stats {
+		switch s.Stat.Name {
+		case "hp":
+			info.Stats.HP = s.BaseStat
+		case "attack":
+			info.Stats.Attack = s.BaseStat
+		case "defense":
+			info.Stats.Defense = s.BaseStat
+		case "special-attack":
+			info.Stats.SpAtk = s.BaseStat
+		case "special-defense":
+			info.Stats.SpDef = s.BaseStat
+		case "speed":
+			info.Stats.Speed = s.BaseStat
+		}
+	}
+	return info, nil
+}
+
+// ParseLearnset reads the abilities a species can have and the moves it
+// can learn from a raw pokeapi pokemon response.
+func ParseLearnset(pokemonDataRaw []byte) (abilities, moves []string, err error) {
+	var data struct {
+		Abilities []struct {
+			Ability struct {
+				Name string `json:"name"`
+			} `json:"ability"`
+		} `json:"abilities"`
+		Moves []struct {
+			Move struct {
+				Name string `json:"name"`
+			} `json:"move"`
+		} `json:"moves"`
+	}
+	if err := json.Unmarshal(pokemonDataRaw, &data); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse pokemon data: %w", err)
+	}
+
+	for _, a := range data.Abilities {
+		abilities = append(abilities, a.Ability.Name)
+	}
+	for _, m := range data.Moves {
+		moves = append(moves, m.Move.Name)
+	}
+	return abilities, moves, nil
+}
@@ -0,0 +1,557 @@
+package pokedex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DexEntry marks a species as seen or caught in the Pokedex. A caught
+// species stays caught even if every Pokemon of it is later released.
+type DexEntry struct {
+	ID     int          `json:"id"`
+	Name   string       `json:"name"`
+	Caught bool         `json:"caught"`
+	Info   *SpeciesInfo `json:"info,omitempty"`
+}
+
+// Pokemon is an individual caught Pokemon along with how it was caught.
+// Pokemon migrated from saves older than version 2 have no catch details.
+type Pokemon struct {
+	InstanceID int       `json:"instance_id"`
+	SpeciesID  int       `json:"species_id"`
+	Species    string    `json:"species"`
+	Nickname   string    `json:"nickname,omitempty"`
+	Nature     string    `json:"nature,omitempty"`
+	CaughtAt   time.Time `json:"caught_at,omitzero"`
+	Location   string    `json:"location,omitempty"`
+	Attempts   int       `json:"attempts,omitempty"`
+	Level      int       `json:"level,omitempty"`
+	Ball       string    `json:"ball,omitempty"`
+	Shiny      bool      `json:"shiny,omitempty"`
+	// Ability, Item, EVs, IVs and Moves are only known for Pokemon
+	// imported from a team paste. Names are pokeapi identifiers.
+	Ability string     `json:"ability,omitempty"`
+	Item    string     `json:"item,omitempty"`
+	EVs     StatSpread `json:"evs,omitzero"`
+	IVs     StatSpread `json:"ivs,omitzero"`
+	Moves   [4]string  `json:"moves,omitzero"`
+	// OT, OTID and DVs are only known for Pokemon imported from a Gen 1
+	// or Gen 2 game save. DVs range from 0 to 15.
+	OT   string     `json:"ot,omitempty"`
+	OTID int        `json:"ot_id,omitempty"`
+	DVs  StatSpread `json:"dvs,omitzero"`
+}
+
+// DisplayName is the nickname followed by the species in parentheses if
+// the Pokemon has one, otherwise just its species.
+func (p Pokemon) DisplayName() string {
+	if p.Nickname == "" {
+		return p.Species
+	}
+	return fmt.Sprintf("%s (%s)", p.Nickname, p.Species)
+}
+
+// Store keeps the species completed in your Pokedex and the individual
+// Pokemon you own.
+type Store interface {
+	// Add saves a newly caught Pokemon under a new instance ID, marks its
+	// species as caught and returns the saved Pokemon. A non-nil info is
+	// stored with the species.
+	Add(p Pokemon, info *SpeciesInfo) (Pokemon, error)
+	// Get finds a Pokemon by instance ID, by nickname, or by species
+	// name when you own exactly one of that species.
+	Get(ref string) (Pokemon, bool, error)
+	List() ([]Pokemon, error)
+	// Remove releases a Pokemon and returns it.
+	Remove(instanceID int) (Pokemon, error)
+	// SetNickname names a Pokemon, or clears its nickname when empty.
+	SetNickname(instanceID int, nickname string) (Pokemon, error)
+	// Import merges exported Pokemon and Dex entries into the save.
+	Import(dex []DexEntry, pokemon []Pokemon) (ImportReport, error)
+	// Has reports whether the species has been caught.
+	Has(species string) (bool, error)
+	// MarkSeen records species as seen, leaving caught ones untouched.
+	MarkSeen(seen ...DexEntry) error
+	// SetInfo stores species data for species already in the Dex.
+	SetInfo(infos map[string]SpeciesInfo) error
+	Dex() ([]DexEntry, error)
+	// Undo reverts the last catch, release or other change to your
+	// collection and returns it.
+	Undo() (Operation, error)
+}
+
+// FileStore saves the Pokedex as a versioned SaveFile. Older saves are
+// migrated when read. Writes are atomic and guarded by an advisory lock,
+// and the previous version is kept next to the save with a .bak suffix.
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) backupPath() string {
+	return s.path + ".bak"
+}
+
+// read returns the raw save, the decoded document and the version it was
+// stored as.
+func (s *FileStore) read() ([]byte, SaveFile, int, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, newSaveFile(), CurrentVersion, nil
+		}
+		return nil, SaveFile{}, 0, fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+
+	save, version, err := decodeSave(data)
+	if err != nil {
+		return nil, SaveFile{}, version, fmt.Errorf("failed to parse %s: %w", s.path, err)
+	}
+	return data, save, version, nil
+}
+
+func (s *FileStore) load() (SaveFile, error) {
+	_, save, _, err := s.read()
+	return save, err
+}
+
+// update applies fn to the save while holding the lock. A save that
+// cannot be parsed is never overwritten.
+func (s *FileStore) update(fn func(save *SaveFile) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	unlock, err := LockFile(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	previous, save, _, err := s.read()
+	if err != nil {
+		return fmt.Errorf("refusing to overwrite the Pokedex save: %w", err)
+	}
+
+	if err := fn(&save); err != nil {
+		return err
+	}
+
+	out, err := encodeSave(save)
+	if err != nil {
+		return fmt.Errorf("failed to marshal save file: %w", err)
+	}
+
+	if previous != nil {
+		if err := WriteFileAtomic(s.backupPath(), previous, 0644); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", s.path, err)
+		}
+	}
+
+	return WriteFileAtomic(s.path, out, 0644)
+}
+
+// Upgrade rewrites a save stored in an older format, keeping the old
+// file as a backup. It returns the version the save was upgraded from,
+// or CurrentVersion if nothing needed to change.
+func (s *FileStore) Upgrade() (int, error) {
+	_, _, version, err := s.read()
+	if err != nil || version == CurrentVersion {
+		return version, err
+	}
+
+	return version, s.update(func(save *SaveFile) error {
+		return nil
+	})
+}
+
+func (s *FileStore) Add(p Pokemon, info *SpeciesInfo) (Pokemon, error) {
+	var added Pokemon
+	err := s.update(func(save *SaveFile) error {
+		added = save.catch(p, info)
+		return nil
+	})
+	return added, err
+}
+
+func (s *FileStore) Get(ref string) (Pokemon, bool, error) {
+	save, err := s.load()
+	if err != nil {
+		return Pokemon{}, false, err
+	}
+	return save.get(ref)
+}
+
+func (s *FileStore) List() ([]Pokemon, error) {
+	save, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sortPokemon(save.Pokemon)
+	return save.Pokemon, nil
+}
+
+func (s *FileStore) Remove(instanceID int) (Pokemon, error) {
+	var removed Pokemon
+	err := s.update(func(save *SaveFile) error {
+		var err error
+		removed, err = save.release(instanceID)
+		return err
+	})
+	return removed, err
+}
+
+func (s *FileStore) SetNickname(instanceID int, nickname string) (Pokemon, error) {
+	var updated Pokemon
+	err := s.update(func(save *SaveFile) error {
+		var err error
+		updated, err = save.setNickname(instanceID, nickname)
+		return err
+	})
+	return updated, err
+}
+
+func (s *FileStore) Import(dex []DexEntry, pokemon []Pokemon) (ImportReport, error) {
+	var report ImportReport
+	err := s.update(func(save *SaveFile) error {
+		report = save.merge(dex, pokemon)
+		return nil
+	})
+	return report, err
+}
+
+func (s *FileStore) Undo() (Operation, error) {
+	var op Operation
+	err := s.update(func(save *SaveFile) error {
+		var err error
+		op, err = save.undo()
+		return err
+	})
+	return op, err
+}
+
+func (s *FileStore) Has(species string) (bool, error) {
+	save, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	return save.has(species), nil
+}
+
+func (s *FileStore) MarkSeen(seen ...DexEntry) error {
+	save, err := s.load()
+	if err != nil {
+		return err
+	}
+	if !save.needsSeen(seen) {
+		return nil
+	}
+
+	return s.update(func(save *SaveFile) error {
+		save.markSeen(seen)
+		return nil
+	})
+}
+
+func (s *FileStore) SetInfo(infos map[string]SpeciesInfo) error {
+	return s.update(func(save *SaveFile) error {
+		save.setInfo(infos)
+		return nil
+	})
+}
+
+func (s *FileStore) Dex() ([]DexEntry, error) {
+	save, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sortDex(save.Dex)
+	return save.Dex, nil
+}
+
+// MemoryStore keeps the Pokedex in memory, for tests.
+type MemoryStore struct {
+	mu   sync.Mutex
+	save SaveFile
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{save: newSaveFile()}
+}
+
+func (s *MemoryStore) Add(p Pokemon, info *SpeciesInfo) (Pokemon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.catch(p, info), nil
+}
+
+func (s *MemoryStore) Get(ref string) (Pokemon, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.get(ref)
+}
+
+func (s *MemoryStore) List() ([]Pokemon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := append([]Pokemon{}, s.save.Pokemon...)
+	sortPokemon(list)
+	return list, nil
+}
+
+func (s *MemoryStore) Remove(instanceID int) (Pokemon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.release(instanceID)
+}
+
+func (s *MemoryStore) SetNickname(instanceID int, nickname string) (Pokemon, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.setNickname(instanceID, nickname)
+}
+
+func (s *MemoryStore) Import(dex []DexEntry, pokemon []Pokemon) (ImportReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.merge(dex, pokemon), nil
+}
+
+func (s *MemoryStore) Undo() (Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.undo()
+}
+
+func (s *MemoryStore) Has(species string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save.has(species), nil
+}
+
+func (s *MemoryStore) MarkSeen(seen ...DexEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.save.markSeen(seen)
+	return nil
+}
+
+func (s *MemoryStore) SetInfo(infos map[string]SpeciesInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.save.setInfo(infos)
+	return nil
+}
+
+func (s *MemoryStore) Dex() ([]DexEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dex := append([]DexEntry{}, s.save.Dex...)
+	sortDex(dex)
+	return dex, nil
+}
+
+func (save *SaveFile) add(p Pokemon, info *SpeciesInfo) Pokemon {
+	p.InstanceID = save.NextInstanceID
+	save.NextInstanceID++
+	save.Pokemon = append(save.Pokemon, p)
+
+	i := save.findDex(p.Species)
+	if i < 0 {
+		save.Dex = append(save.Dex, DexEntry{ID: p.SpeciesID, Name: p.Species})
+		i = len(save.Dex) - 1
+	}
+	save.Dex[i].Caught = true
+	if info != nil {
+		save.Dex[i].Info = info
+	}
+	return p
+}
+
+func (save *SaveFile) catch(p Pokemon, info *SpeciesInfo) Pokemon {
+	dex, absent := save.dexSnapshot([]Pokemon{p})
+	added := save.add(p, info)
+	save.record(Operation{Kind: "catch", After: []Pokemon{added}, Dex: dex, Absent: absent})
+	return added
+}
+
+func (save *SaveFile) release(instanceID int) (Pokemon, error) {
+	i := save.findPokemon(instanceID)
+	if i < 0 {
+		return Pokemon{}, fmt.Errorf("no Pokemon with id #%d in your collection", instanceID)
+	}
+	p := save.Pokemon[i]
+	save.remove(instanceID)
+	save.record(Operation{Kind: "release", Before: []Pokemon{p}})
+	return p, nil
+}
+
+func (save *SaveFile) setNickname(instanceID int, nickname string) (Pokemon, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname != "" {
+		if err := ValidateNickname(nickname); err != nil {
+			return Pokemon{}, err
+		}
+	}
+
+	i := save.findPokemon(instanceID)
+	if i < 0 {
+		return Pokemon{}, fmt.Errorf("no Pokemon with id #%d in your collection", instanceID)
+	}
+	for _, p := range save.Pokemon {
+		if nickname != "" && p.InstanceID != instanceID && strings.EqualFold(p.Nickname, nickname) {
+			return Pokemon{}, fmt.Errorf("%s #%d is already called %s", p.Species, p.InstanceID, p.Nickname)
+		}
+	}
+	if nickname != "" && save.isSpecies(nickname) {
+		return Pokemon{}, fmt.Errorf("%s is the name of a species, pick another nickname", nickname)
+	}
+
+	before := save.Pokemon[i]
+	save.Pokemon[i].Nickname = nickname
+	save.record(Operation{Kind: "nickname", Before: []Pokemon{before}, After: []Pokemon{save.Pokemon[i]}})
+	return save.Pokemon[i], nil
+}
+
+// isSpecies reports whether name is the name of a species in the Dex or
+// the collection, which would make lookups by that name ambiguous.
+func (save *SaveFile) isSpecies(name string) bool {
+	for _, d := range save.Dex {
+		if strings.EqualFold(d.Name, name) {
+			return true
+		}
+	}
+	for _, p := range save.Pokemon {
+		if strings.EqualFold(p.Species, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (save *SaveFile) setInfo(infos map[string]SpeciesInfo) {
+	for i, d := range save.Dex {
+		if info, ok := infos[d.Name]; ok {
+			save.Dex[i].Info = &info
+		}
+	}
+}
+
+func (save *SaveFile) markSeen(seen []DexEntry) {
+	for _, d := range seen {
+		if save.findDex(d.Name) < 0 {
+			save.Dex = append(save.Dex, DexEntry{ID: d.ID, Name: d.Name})
+		}
+	}
+}
+
+// needsSeen reports whether markSeen would change anything, so that
+// exploring an area already seen doesn't rewrite the save.
+func (save *SaveFile) needsSeen(seen []DexEntry) bool {
+	for _, d := range seen {
+		if save.findDex(d.Name) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (save *SaveFile) findDex(species string) int {
+	for i, d := range save.Dex {
+		if d.Name == species {
+			return i
+		}
+	}
+	return -1
+}
+
+func (save *SaveFile) get(ref string) (Pokemon, bool, error) {
+	if id, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		for _, p := range save.Pokemon {
+			if p.InstanceID == id {
+				return p, true, nil
+			}
+		}
+		return Pokemon{}, false, nil
+	}
+
+	for _, p := range save.Pokemon {
+		if p.Nickname != "" && strings.EqualFold(p.Nickname, ref) {
+			return p, true, nil
+		}
+	}
+
+	var matches []Pokemon
+	for _, p := range save.Pokemon {
+		if p.Species == ref {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Pokemon{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	}
+
+	ids := make([]string, len(matches))
+	for i, p := range matches {
+		ids[i] = fmt.Sprintf("#%d", p.InstanceID)
+	}
+	return Pokemon{}, false, fmt.Errorf("you have %d %s, pick one by id: %s", len(matches), ref, strings.Join(ids, ", "))
+}
+
+func (save *SaveFile) remove(instanceID int) {
+	if i := save.findPokemon(instanceID); i >= 0 {
+		save.Pokemon = append(save.Pokemon[:i], save.Pokemon[i+1:]...)
+	}
+}
+
+func (save *SaveFile) findPokemon(instanceID int) int {
+	for i, p := range save.Pokemon {
+		if p.InstanceID == instanceID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (save *SaveFile) has(species string) bool {
+	i := save.findDex(species)
+	return i >= 0 && save.Dex[i].Caught
+}
+
+func sortPokemon(list []Pokemon) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].InstanceID < list[j].InstanceID
+	})
+}
+
+func sortDex(dex []DexEntry) {
+	sort.Slice(dex, func(i, j int) bool {
+		if dex[i].ID != dex[j].ID {
+			return dex[i].ID < dex[j].ID
+		}
+		return dex[i].Name < dex[j].Name
+	})
+}
@@ -0,0 +1,183 @@
+package pokedex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, store Store) {
+	catches := []Pokemon{
+		{SpeciesID: 25, Species: "pikachu", Level: 5},
+		{SpeciesID: 1, Species: "bulbasaur"},
+		{SpeciesID: 25, Species: "pikachu", Level: 12},
+	}
+	for i, p := range catches {
+		added, err := store.Add(p, nil)
+		if err != nil {
+			t.Fatalf("unexpected error adding %s: %v", p.Species, err)
+		}
+		if added.InstanceID != i+1 {
+			t.Errorf("expected instance id %d, got %d", i+1, added.InstanceID)
+		}
+	}
+
+	dex, err := store.Dex()
+	if err != nil {
+		t.Fatalf("unexpected error listing dex: %v", err)
+	}
+	expected := []string{"bulbasaur", "pikachu"}
+	if len(dex) != len(expected) {
+		t.Fatalf("len of actual not the same as expected. Actual: %d - vs - Expected: %d", len(dex), len(expected))
+	}
+	for i := range dex {
+		if dex[i].Name != expected[i] {
+			t.Errorf("dex not sorted by id. Actual: %s - vs - Expected: %s", dex[i].Name, expected[i])
+		}
+	}
+
+	list, err := store.List()
+	if err != nil || len(list) != 3 {
+		t.Fatalf("expected 3 pokemon, got %d (%v)", len(list), err)
+	}
+
+	if _, _, err := store.Get("pikachu"); err == nil {
+		t.Errorf("expected an error getting an ambiguous species")
+	}
+	p, ok, err := store.Get("#3")
+	if err != nil || !ok || p.Level != 12 {
+		t.Errorf("expected to get pikachu #3, got %v %v (%v)", p, ok, err)
+	}
+	p, ok, err = store.Get("bulbasaur")
+	if err != nil || !ok || p.InstanceID != 2 {
+		t.Errorf("expected to get bulbasaur by species, got %v %v (%v)", p, ok, err)
+	}
+
+	if _, err := store.Remove(1); err != nil {
+		t.Errorf("unexpected error removing: %v", err)
+	}
+	if _, err := store.Remove(1); err == nil {
+		t.Errorf("expected an error removing a missing pokemon")
+	}
+	p, ok, err = store.Get("pikachu")
+	if err != nil || !ok || p.InstanceID != 3 {
+		t.Errorf("expected the remaining pikachu, got %v %v (%v)", p, ok, err)
+	}
+
+	if ok, _ := store.Has("pikachu"); !ok {
+		t.Errorf("expected species to stay caught after a release")
+	}
+	if err := store.MarkSeen(DexEntry{ID: 7, Name: "squirtle"}, DexEntry{ID: 25, Name: "pikachu"}); err != nil {
+		t.Fatalf("unexpected error marking seen: %v", err)
+	}
+	if ok, _ := store.Has("squirtle"); ok {
+		t.Errorf("expected a seen species to not count as caught")
+	}
+	if ok, _ := store.Has("pikachu"); !ok {
+		t.Errorf("expected marking seen to leave caught species caught")
+	}
+
+	added, _ := store.Add(Pokemon{SpeciesID: 7, Species: "squirtle"}, nil)
+	if added.InstanceID != 4 {
+		t.Errorf("expected instance ids to never be reused, got %d", added.InstanceID)
+	}
+	if ok, _ := store.Has("squirtle"); !ok {
+		t.Errorf("expected a seen species to become caught")
+	}
+	if dex, _ := store.Dex(); len(dex) != 3 {
+		t.Errorf("expected 3 species in the dex, got %d", len(dex))
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, NewFileStore(filepath.Join(t.TempDir(), "caught.json")))
+}
+
+func TestFileStoreReadsCaughtJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "caught.json")
+	if err := os.WriteFile(path, []byte(`{"25": "pikachu", "4": "charmander"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewFileStore(path)
+	p, ok, err := store.Get("charmander")
+	if err != nil || !ok || p.SpeciesID != 4 {
+		t.Errorf("expected to get charmander, got %v %v (%v)", p, ok, err)
+	}
+}
+
+func TestFileStoreKeepsBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "caught.json")
+	store := NewFileStore(path)
+
+	if _, err := store.Add(Pokemon{SpeciesID: 25, Species: "pikachu"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup for a new save")
+	}
+
+	before, _ := os.ReadFile(path)
+	if _, err := store.Add(Pokemon{SpeciesID: 1, Species: "bulbasaur"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != string(before) {
+		t.Errorf("expected backup of previous save, got %q (%v)", backup, err)
+	}
+}
+
+func TestFileStoreRefusesCorruptSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "caught.json")
+	corrupt := []byte(`{"25": "pika`)
+	if err := os.WriteFile(path, corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewFileStore(path)
+	if _, err := store.Add(Pokemon{SpeciesID: 1, Species: "bulbasaur"}, nil); err == nil {
+		t.Errorf("expected an error adding to a corrupt save")
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != string(corrupt) {
+		t.Errorf("expected corrupt save to be left untouched, got %q", data)
+	}
+}
+
+func TestFileStoreKeepsCatchDetails(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "caught.json"))
+	caughtAt := time.Date(2026, 10, 19, 14, 3, 0, 0, time.UTC)
+	expected := Pokemon{
+		SpeciesID: 25,
+		Species:   "pikachu",
+		Nature:    "timid",
+		CaughtAt:  caughtAt,
+		Location:  "viridian-forest-area",
+		Attempts:  3,
+		Level:     7,
+		Ball:      "Great Ball",
+		Shiny:     true,
+	}
+	expected, err := store.Add(expected, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, _, err := store.Get("pikachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actual.CaughtAt.Equal(caughtAt) {
+		t.Errorf("caught at does not match. Actual: %v - vs - Expected: %v", actual.CaughtAt, caughtAt)
+	}
+	actual.CaughtAt = caughtAt
+	if actual != expected {
+		t.Errorf("pokemon does not match. Actual: %v - vs - Expected: %v", actual, expected)
+	}
+}
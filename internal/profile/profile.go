@@ -0,0 +1,191 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+const (
+	// DefaultName is the profile used until another one is picked.
+	DefaultName = "default"
+
+	profilesDir  = "profiles"
+	currentFile  = "current-profile"
+	saveFile     = "caught.json"
+	settingsFile = "settings.json"
+	statsFile    = "stats.json"
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,19}$`)
+
+// ValidateName checks that a profile name is safe to use as a directory
+// name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use up to 20 lowercase letters, digits, - or _", name)
+	}
+	return nil
+}
+
+// Profile is one trainer's directory, holding their save, settings and
+// statistics.
+type Profile struct {
+	Name string
+	Dir  string
+}
+
+func (p Profile) SavePath() string {
+	return filepath.Join(p.Dir, saveFile)
+}
+
+// Store opens the profile's Pokedex save.
+func (p Profile) Store() *pokedex.FileStore {
+	return pokedex.NewFileStore(p.SavePath())
+}
+
+// Manager finds and creates profiles inside the data directory.
+type Manager struct {
+	dir string
+}
+
+func NewManager(dataDir string) *Manager {
+	return &Manager{dir: dataDir}
+}
+
+func (m *Manager) profile(name string) Profile {
+	return Profile{Name: name, Dir: filepath.Join(m.dir, profilesDir, name)}
+}
+
+// List returns the names of all profiles, sorted.
+func (m *Manager) List() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.dir, profilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateName(entry.Name()) == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Create makes a new, empty profile.
+func (m *Manager) Create(name string) (Profile, error) {
+	if err := ValidateName(name); err != nil {
+		return Profile{}, err
+	}
+
+	p := m.profile(name)
+	if err := os.MkdirAll(filepath.Dir(p.Dir), 0755); err != nil {
+		return Profile{}, fmt.Errorf("failed to create profiles directory: %w", err)
+	}
+	if err := os.Mkdir(p.Dir, 0755); err != nil {
+		if os.IsExist(err) {
+			return Profile{}, fmt.Errorf("profile %s already exists", name)
+		}
+		return Profile{}, fmt.Errorf("failed to create profile %s: %w", name, err)
+	}
+	return p, nil
+}
+
+// Open returns an existing profile. The default profile is created the
+// first time it is opened.
+func (m *Manager) Open(name string) (Profile, error) {
+	if err := ValidateName(name); err != nil {
+		return Profile{}, err
+	}
+
+	p := m.profile(name)
+	if _, err := os.Stat(p.Dir); err != nil {
+		if !os.IsNotExist(err) {
+			return Profile{}, fmt.Errorf("failed to open profile %s: %w", name, err)
+		}
+		if name != DefaultName {
+			return Profile{}, fmt.Errorf("profile %s does not exist, create it with: profile new %s", name, name)
+		}
+		return m.Create(name)
+	}
+	return p, nil
+}
+
+// Current returns the name of the profile picked with SetCurrent, or the
+// default profile.
+func (m *Manager) Current() string {
+	data, err := os.ReadFile(filepath.Join(m.dir, currentFile))
+	if err != nil {
+		return DefaultName
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateName(name) != nil {
+		return DefaultName
+	}
+	return name
+}
+
+// SetCurrent remembers the profile to open on the next start.
+func (m *Manager) SetCurrent(name string) error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return pokedex.WriteFileAtomic(filepath.Join(m.dir, currentFile), []byte(name+"\n"), 0644)
+}
+
+// MigrateSave moves a save from before profiles, which lived directly in
+// the data directory, into the default profile. It reports whether
+// anything was moved, and errors when the old save is left behind because
+// the default profile already has one.
+func (m *Manager) MigrateSave() (bool, error) {
+	old := filepath.Join(m.dir, saveFile)
+	if _, err := os.Stat(old); err != nil {
+		return false, nil
+	}
+
+	p := m.profile(DefaultName)
+	if _, err := os.Stat(p.SavePath()); err == nil {
+		return false, fmt.Errorf("found a save from before profiles at %s, but profile %s already has one: move it into a new profile or delete it", old, DefaultName)
+	}
+	if err := os.MkdirAll(p.Dir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create profile %s: %w", DefaultName, err)
+	}
+
+	for _, name := range []string{saveFile, saveFile + ".bak"} {
+		err := os.Rename(filepath.Join(m.dir, name), filepath.Join(p.Dir, name))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("failed to move %s into profile %s: %w", name, DefaultName, err)
+		}
+	}
+	return true, nil
+}
+
+// readJSON decodes path into v, leaving v untouched if the file does not
+// exist yet.
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return pokedex.WriteFileAtomic(path, data, 0644)
+}
@@ -0,0 +1,120 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManager(t *testing.T) {
+	m := NewManager(t.TempDir())
+
+	if m.Current() != DefaultName {
+		t.Errorf("current profile does not match. Actual: %s - vs - Expected: %s", m.Current(), DefaultName)
+	}
+	if _, err := m.Open("misty"); err == nil {
+		t.Errorf("expected an error opening a missing profile")
+	}
+	if _, err := m.Open(DefaultName); err != nil {
+		t.Errorf("expected the default profile to be created, got %v", err)
+	}
+
+	if _, err := m.Create("misty"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Create("misty"); err == nil {
+		t.Errorf("expected an error creating a profile twice")
+	}
+	if _, err := m.Create("../ash"); err == nil {
+		t.Errorf("expected an error for an invalid name")
+	}
+
+	names, err := m.List()
+	expected := []string{"default", "misty"}
+	if err != nil || len(names) != len(expected) {
+		t.Fatalf("expected profiles %v, got %v (%v)", expected, names, err)
+	}
+	for i := range names {
+		if names[i] != expected[i] {
+			t.Errorf("profiles not sorted. Actual: %s - vs - Expected: %s", names[i], expected[i])
+		}
+	}
+
+	if err := m.SetCurrent("misty"); err != nil {
+		t.Fatal(err)
+	}
+	if m.Current() != "misty" {
+		t.Errorf("current profile does not match. Actual: %s - vs - Expected: %s", m.Current(), "misty")
+	}
+}
+
+func TestMigrateSave(t *testing.T) {
+	dir := t.TempDir()
+	save := []byte(`{"25": "pikachu"}`)
+	if err := os.WriteFile(filepath.Join(dir, "caught.json"), save, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(dir)
+	moved, err := m.MigrateSave()
+	if err != nil || !moved {
+		t.Fatalf("expected the save to be moved, got %v (%v)", moved, err)
+	}
+
+	p, _ := m.Open(DefaultName)
+	data, err := os.ReadFile(p.SavePath())
+	if err != nil || string(data) != string(save) {
+		t.Errorf("expected save in the default profile, got %q (%v)", data, err)
+	}
+	if moved, err := m.MigrateSave(); moved || err != nil {
+		t.Errorf("expected nothing to move the second time, got %v (%v)", moved, err)
+	}
+
+	// an old save next to an existing profile save is left alone, loudly
+	if err := os.WriteFile(filepath.Join(dir, "caught.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := m.MigrateSave(); moved || err == nil {
+		t.Errorf("expected the left behind save to be reported, got %v (%v)", moved, err)
+	}
+	data, err = os.ReadFile(p.SavePath())
+	if err != nil || string(data) != string(save) {
+		t.Errorf("expected the profile save to be kept, got %q (%v)", data, err)
+	}
+}
+
+func TestSettingsAndStats(t *testing.T) {
+	m := NewManager(t.TempDir())
+	ash, _ := m.Create("ash")
+	misty, _ := m.Create("misty")
+
+	settings, _ := ash.Settings()
+	if err := settings.Set("ball", "ultraball"); err != nil {
+		t.Fatal(err)
+	}
+	if err := settings.Set("favorite", "pikachu"); err == nil {
+		t.Errorf("expected an error for an unknown setting")
+	}
+	if err := ash.SaveSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if err := ash.UpdateStats(func(s *Stats) { s.Thrown++ }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s, _ := ash.Settings(); s.Ball != "ultraball" {
+		t.Errorf("ball does not match. Actual: %s - vs - Expected: %s", s.Ball, "ultraball")
+	}
+	if s, _ := ash.Stats(); s.Thrown != 3 {
+		t.Errorf("thrown does not match. Actual: %d - vs - Expected: %d", s.Thrown, 3)
+	}
+	if s, _ := misty.Settings(); s.Ball != "" {
+		t.Errorf("expected settings to be kept per profile, got %q", s.Ball)
+	}
+	if s, _ := misty.Stats(); s.Thrown != 0 {
+		t.Errorf("expected stats to be kept per profile, got %d", s.Thrown)
+	}
+}
@@ -0,0 +1,78 @@
+package profile
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// Settings are a trainer's preferences. Zero values mean the built-in
+// default.
+type Settings struct {
+	Ball string `json:"ball,omitempty"`
+	// CardCache keeps rendered cards as text when "on".
+	CardCache string `json:"card_cache,omitempty"`
+	// Sprite picks how sprites are drawn, detected from the terminal
+	// when empty.
+	Sprite string `json:"sprite,omitempty"`
+	// Color is auto, always or never, auto when empty.
+	Color string `json:"color,omitempty"`
+	// Theme styles badges and cards, the default theme when empty.
+	Theme string `json:"theme,omitempty"`
+	// Scaling picks how sprites are resized, nearest when empty.
+	Scaling string `json:"scaling,omitempty"`
+}
+
+// settingKeys maps the names used by `profile set` to their fields.
+var settingKeys = map[string]func(s *Settings) *string{
+	"ball":       func(s *Settings) *string { return &s.Ball },
+	"card-cache": func(s *Settings) *string { return &s.CardCache },
+	"color":      func(s *Settings) *string { return &s.Color },
+	"scaling":    func(s *Settings) *string { return &s.Scaling },
+	"sprite":     func(s *Settings) *string { return &s.Sprite },
+	"theme":      func(s *Settings) *string { return &s.Theme },
+}
+
+// SettingNames lists the settings that can be changed.
+func SettingNames() []string {
+	names := make([]string, 0, len(settingKeys))
+	for name := range settingKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns a setting by name.
+func (s Settings) Get(key string) (string, bool) {
+	field, ok := settingKeys[key]
+	if !ok {
+		return "", false
+	}
+	return *field(&s), true
+}
+
+// Set changes a setting by name. An empty value restores the default.
+func (s *Settings) Set(key, value string) error {
+	field, ok := settingKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	*field(s) = value
+	return nil
+}
+
+func (p Profile) Settings() (Settings, error) {
+	var s Settings
+	if err := readJSON(filepath.Join(p.Dir, settingsFile), &s); err != nil {
+		return Settings{}, fmt.Errorf("failed to read settings of %s: %w", p.Name, err)
+	}
+	return s, nil
+}
+
+func (p Profile) SaveSettings(s Settings) error {
+	if err := writeJSON(filepath.Join(p.Dir, settingsFile), s); err != nil {
+		return fmt.Errorf("failed to save settings of %s: %w", p.Name, err)
+	}
+	return nil
+}
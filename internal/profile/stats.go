@@ -0,0 +1,54 @@
+package profile
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+)
+
+// Stats count what a trainer has done over all their sessions.
+type Stats struct {
+	Explored int `json:"explored"`
+	Thrown   int `json:"thrown"`
+	Caught   int `json:"caught"`
+	Escaped  int `json:"escaped"`
+	Released int `json:"released"`
+}
+
+// CatchRate is the share of throws that caught a Pokemon, in percent.
+func (s Stats) CatchRate() float64 {
+	if s.Thrown == 0 {
+		return 0
+	}
+	return float64(s.Caught) * 100 / float64(s.Thrown)
+}
+
+func (p Profile) Stats() (Stats, error) {
+	var s Stats
+	if err := readJSON(filepath.Join(p.Dir, statsFile), &s); err != nil {
+		return Stats{}, fmt.Errorf("failed to read stats of %s: %w", p.Name, err)
+	}
+	return s, nil
+}
+
+// UpdateStats applies fn to the stored statistics and saves them. Like
+// the Pokedex save, the file is locked while it is updated so REPLs
+// playing the same profile don't lose each other's counts.
+func (p Profile) UpdateStats(fn func(s *Stats)) error {
+	unlock, err := pokedex.LockFile(filepath.Join(p.Dir, statsFile))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	s, err := p.Stats()
+	if err != nil {
+		return err
+	}
+	fn(&s)
+	if err := writeJSON(filepath.Join(p.Dir, statsFile), s); err != nil {
+		return fmt.Errorf("failed to save stats of %s: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package profile
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUpdateStatsConcurrently(t *testing.T) {
+	m := NewManager(t.TempDir())
+	if _, err := m.Create("ash"); err != nil {
+		t.Fatal(err)
+	}
+
+	// every REPL opens the profile on its own
+	const sessions = 20
+	var wg sync.WaitGroup
+	for range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ash, _ := m.Open("ash")
+			if err := ash.UpdateStats(func(s *Stats) { s.Thrown++ }); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ash, _ := m.Open("ash")
+	if s, _ := ash.Stats(); s.Thrown != sessions {
+		t.Errorf("thrown does not match. Actual: %d - vs - Expected: %d", s.Thrown, sessions)
+	}
+}
@@ -0,0 +1,64 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColorModes are the values of --color and the color setting. auto
+// colors output unless NO_COLOR is set or stdout isn't a terminal.
+var ColorModes = []string{"auto", "always", "never"}
+
+var depth = TrueColor
+
+// SetDepth sets the colors styled output uses for the whole program,
+// NoColor to turn styling off.
+func SetDepth(d Depth) {
+	depth = d
+}
+
+// CurrentDepth is the depth set by SetDepth.
+func CurrentDepth() Depth {
+	return depth
+}
+
+// Color reports whether output should be styled with escape sequences.
+func Color() bool {
+	return depth != NoColor
+}
+
+// ColorEnabled decides whether to style output in a color mode. An
+// empty mode is auto.
+func ColorEnabled(mode string, getenv func(string) string, tty bool) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		// https://no-color.org: any non-empty value turns color off
+		return getenv("NO_COLOR") == "" && tty, nil
+	}
+	return false, fmt.Errorf("color must be one of %s", strings.Join(ColorModes, ", "))
+}
+
+// IsTerminal reports whether f is a terminal rather than a pipe or file.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+var escapePattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Strip removes color escapes from s.
+func Strip(s string) string {
+	return escapePattern.ReplaceAllString(s, "")
+}
+
+// VisibleWidth counts the columns s takes up, skipping color escapes.
+func VisibleWidth(s string) int {
+	return utf8.RuneCountInString(Strip(s))
+}
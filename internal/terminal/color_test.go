@@ -0,0 +1,49 @@
+package terminal
+
+import "testing"
+
+func TestColorEnabled(t *testing.T) {
+	cases := []struct {
+		mode     string
+		noColor  string
+		tty      bool
+		expected bool
+	}{
+		{mode: "auto", tty: true, expected: true},
+		{mode: "", tty: true, expected: true},
+		{mode: "auto", tty: false, expected: false},
+		{mode: "auto", noColor: "1", tty: true, expected: false},
+		{mode: "always", noColor: "1", tty: false, expected: true},
+		{mode: "never", tty: true, expected: false},
+	}
+
+	for _, c := range cases {
+		getenv := func(key string) string {
+			if key == "NO_COLOR" {
+				return c.noColor
+			}
+			return ""
+		}
+		actual, err := ColorEnabled(c.mode, getenv, c.tty)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", c.mode, err)
+		}
+		if actual != c.expected {
+			t.Errorf("mode %q NO_COLOR=%q tty=%v: Actual: %v - vs - Expected: %v", c.mode, c.noColor, c.tty, actual, c.expected)
+		}
+	}
+
+	if _, err := ColorEnabled("sometimes", func(string) string { return "" }, true); err == nil {
+		t.Errorf("expected an error for an unknown color mode")
+	}
+}
+
+func TestStrip(t *testing.T) {
+	actual := Strip("\x1b[48;2;248;208;48m\x1b[38;2;255;255;255m Electric \x1b[0m")
+	if actual != " Electric " {
+		t.Errorf("stripped does not match. Actual: %q - vs - Expected: %q", actual, " Electric ")
+	}
+	if width := VisibleWidth("\x1b[1mPokémon\x1b[0m"); width != 7 {
+		t.Errorf("width does not match. Actual: %d - vs - Expected: %d", width, 7)
+	}
+}
@@ -0,0 +1,145 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Depth is how many colors a terminal can show.
+type Depth int
+
+const (
+	NoColor Depth = iota
+	Color16
+	Color256
+	TrueColor
+)
+
+// DetectDepth reads the colors the terminal advertises through COLORTERM
+// and TERM.
+func DetectDepth(getenv func(string) string) Depth {
+	colorterm := getenv("COLORTERM")
+	term := getenv("TERM")
+	switch {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return Color256
+	case term == "dumb":
+		return NoColor
+	}
+	return Color16
+}
+
+// RGB is an 8-bit color.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Foreground returns the escape sequence setting the text color at this
+// depth, or nothing without color.
+func (d Depth) Foreground(c RGB) string {
+	switch d {
+	case TrueColor:
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Color256:
+		return fmt.Sprintf("\x1b[38;5;%dm", Nearest256(c))
+	case Color16:
+		i := Nearest16(c)
+		if i < 8 {
+			return fmt.Sprintf("\x1b[%dm", 30+i)
+		}
+		return fmt.Sprintf("\x1b[%dm", 90+i-8)
+	}
+	return ""
+}
+
+func (d Depth) Background(c RGB) string {
+	switch d {
+	case TrueColor:
+		return fmt.Sprintf("\x1b[48;2;%d;%d;%dm", c.R, c.G, c.B)
+	case Color256:
+		return fmt.Sprintf("\x1b[48;5;%dm", Nearest256(c))
+	case Color16:
+		i := Nearest16(c)
+		if i < 8 {
+			return fmt.Sprintf("\x1b[%dm", 40+i)
+		}
+		return fmt.Sprintf("\x1b[%dm", 100+i-8)
+	}
+	return ""
+}
+
+func distance(a, b RGB) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
+
+// cubeLevels are the channel values of the xterm 6x6x6 color cube.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+func nearestLevel(v uint8) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if absDiff(v, level) < absDiff(v, cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// Nearest256 picks the closest xterm-256 color from the color cube or
+// the grayscale ramp, skipping the first 16 colors as terminals change
+// them.
+func Nearest256(c RGB) int {
+	r, g, b := nearestLevel(c.R), nearestLevel(c.G), nearestLevel(c.B)
+	cube := RGB{cubeLevels[r], cubeLevels[g], cubeLevels[b]}
+	index := 16 + 36*r + 6*g + b
+
+	gray := (int(c.R) + int(c.G) + int(c.B)) / 3
+	step := min(max((gray-8+5)/10, 0), 23)
+	level := uint8(8 + 10*step)
+	if distance(c, RGB{level, level, level}) < distance(c, cube) {
+		return 232 + step
+	}
+	return index
+}
+
+// Palette256 returns the color of an xterm-256 palette index from the
+// color cube or the grayscale ramp.
+func Palette256(index int) RGB {
+	if index >= 232 {
+		level := uint8(8 + 10*(index-232))
+		return RGB{level, level, level}
+	}
+	if index < 16 {
+		return ansi16[index]
+	}
+	index -= 16
+	return RGB{cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]}
+}
+
+// ansi16 are the xterm defaults of the 16 basic colors.
+var ansi16 = [16]RGB{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+func Nearest16(c RGB) int {
+	best := 0
+	for i, candidate := range ansi16 {
+		if distance(c, candidate) < distance(c, ansi16[best]) {
+			best = i
+		}
+	}
+	return best
+}
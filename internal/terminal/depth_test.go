@@ -0,0 +1,32 @@
+package terminal
+
+import "testing"
+
+func TestColorQuantization(t *testing.T) {
+	cases := []struct {
+		color     RGB
+		expected  int
+		expected6 int
+	}{
+		{color: RGB{255, 0, 0}, expected: 196, expected6: 9},
+		{color: RGB{0, 0, 0}, expected: 16, expected6: 0},
+		{color: RGB{128, 128, 128}, expected: 244, expected6: 8},
+		{color: RGB{248, 208, 48}, expected: 221, expected6: 3},
+	}
+
+	for _, c := range cases {
+		if actual := Nearest256(c.color); actual != c.expected {
+			t.Errorf("256 color of %v: Actual: %d - vs - Expected: %d", c.color, actual, c.expected)
+		}
+		if actual := Nearest16(c.color); actual != c.expected6 {
+			t.Errorf("16 color of %v: Actual: %d - vs - Expected: %d", c.color, actual, c.expected6)
+		}
+	}
+
+	if actual := Color16.Foreground(RGB{255, 0, 0}); actual != "\x1b[91m" {
+		t.Errorf("16 color escape does not match. Actual: %q - vs - Expected: %q", actual, "\x1b[91m")
+	}
+	if actual := Color256.Background(RGB{255, 0, 0}); actual != "\x1b[48;5;196m" {
+		t.Errorf("256 color escape does not match. Actual: %q - vs - Expected: %q", actual, "\x1b[48;5;196m")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package terminal
+
+// windowWidth is not available here, so Width relies on $COLUMNS.
+func windowWidth(fd uintptr) (int, bool) {
+	return 0, false
+}
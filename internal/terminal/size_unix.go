@@ -0,0 +1,20 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package terminal
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// windowWidth asks the terminal driver for the window size.
+func windowWidth(fd uintptr) (int, bool) {
+	var size struct {
+		Rows, Cols, X, Y uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0, false
+	}
+	return int(size.Cols), true
+}
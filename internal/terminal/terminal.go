@@ -0,0 +1,23 @@
+// Package terminal finds out what the terminal the Pokedex runs in can
+// show.
+package terminal
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is assumed when the width can't be found out.
+const DefaultWidth = 80
+
+// Width returns the number of columns of the terminal on stdout, then
+// tries $COLUMNS, then falls back to DefaultWidth.
+func Width() int {
+	if width, ok := windowWidth(os.Stdout.Fd()); ok && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DefaultWidth
+}
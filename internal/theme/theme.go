@@ -0,0 +1,148 @@
+// Package theme holds the styles of type badges, card headers and the
+// Pokedex grid. Themes are JSON files of RGB colors, turned into escape
+// sequences for the color depth of the terminal.
+package theme
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+//go:embed themes/*.json
+var files embed.FS
+
+const reset = "\x1b[0m"
+
+// DefaultName is the theme used unless another is set.
+const DefaultName = "default"
+
+// Color is an RGB color written as #rrggbb.
+type Color terminal.RGB
+
+func (c *Color) UnmarshalText(text []byte) error {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(string(text), "#%02x%02x%02x", &r, &g, &b); err != nil || len(text) != 7 {
+		return fmt.Errorf("invalid color %q, use #rrggbb", text)
+	}
+	*c = Color{R: r, G: g, B: b}
+	return nil
+}
+
+// Style is how a piece of text looks. Colors left out keep the
+// terminal's own.
+type Style struct {
+	FG   *Color `json:"fg,omitempty"`
+	BG   *Color `json:"bg,omitempty"`
+	Bold bool   `json:"bold,omitempty"`
+	Dim  bool   `json:"dim,omitempty"`
+}
+
+// Paint wraps text in the escapes of the style at a depth. Without color
+// text is returned as is.
+func (s Style) Paint(d terminal.Depth, text string) string {
+	if d == terminal.NoColor {
+		return text
+	}
+
+	var escapes strings.Builder
+	if s.Bold {
+		escapes.WriteString("\x1b[1m")
+	}
+	if s.Dim {
+		escapes.WriteString("\x1b[2m")
+	}
+	if s.BG != nil {
+		escapes.WriteString(d.Background(terminal.RGB(*s.BG)))
+	}
+	if s.FG != nil {
+		escapes.WriteString(d.Foreground(terminal.RGB(*s.FG)))
+	}
+	if escapes.Len() == 0 {
+		return text
+	}
+	return escapes.String() + text + reset
+}
+
+// Theme is the set of styles the Pokedex draws with.
+type Theme struct {
+	Name   string `json:"name"`
+	Header Style  `json:"header"`
+	Label  Style  `json:"label"`
+	Caught Style  `json:"caught"`
+	Seen   Style  `json:"seen"`
+	// Bars color stat bars, from the weakest stats to the strongest.
+	Bars  []Style          `json:"bars"`
+	Types map[string]Style `json:"types"`
+}
+
+// strongStat is the base stat from which bars get the last style.
+const strongStat = 150
+
+// StatStyle is the style of the bar of a base stat.
+func (t Theme) StatStyle(value int) Style {
+	if len(t.Bars) == 0 {
+		return Style{}
+	}
+	return t.Bars[min(max(value, 0)*len(t.Bars)/strongStat, len(t.Bars)-1)]
+}
+
+// Badge shows a type as a colored badge, or by name alone without color.
+func (t Theme) Badge(d terminal.Depth, typ string) string {
+	if typ == "" {
+		return ""
+	}
+	name := strings.ToUpper(typ[:1]) + typ[1:]
+	if d == terminal.NoColor {
+		return name
+	}
+	return t.Types[typ].Paint(d, " "+name+" ")
+}
+
+// Names lists the themes that can be picked.
+func Names() []string {
+	entries, _ := files.ReadDir("themes")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	return names
+}
+
+// Load reads a theme by name.
+func Load(name string) (Theme, error) {
+	data, err := files.ReadFile("themes/" + name + ".json")
+	if err != nil {
+		return Theme{}, fmt.Errorf("unknown theme %q, themes are %s", name, strings.Join(Names(), ", "))
+	}
+
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, fmt.Errorf("failed to parse theme %s: %w", name, err)
+	}
+	return t, nil
+}
+
+var current = mustLoad(DefaultName)
+
+func mustLoad(name string) Theme {
+	t, err := Load(name)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Set changes the theme used by the whole program.
+func Set(t Theme) {
+	current = t
+}
+
+// Current is the theme set by Set, the default one until then.
+func Current() Theme {
+	return current
+}
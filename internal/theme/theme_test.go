@@ -0,0 +1,109 @@
+package theme
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
+)
+
+func TestThemesLoad(t *testing.T) {
+	expected := []string{"colorblind-safe", "default", "high-contrast", "light"}
+	if names := Names(); !slices.Equal(names, expected) {
+		t.Fatalf("themes do not match. Actual: %v - vs - Expected: %v", names, expected)
+	}
+
+	base, err := Load(DefaultName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := slices.Sorted(maps.Keys(base.Types))
+	if len(types) != 18 {
+		t.Errorf("len of actual not the same as expected. Actual: %d - vs - Expected: %d", len(types), 18)
+	}
+
+	for _, name := range expected {
+		theme, err := Load(name)
+		if err != nil {
+			t.Fatalf("unexpected error loading %s: %v", name, err)
+		}
+		if theme.Name != name {
+			t.Errorf("name does not match. Actual: %s - vs - Expected: %s", theme.Name, name)
+		}
+		if actual := slices.Sorted(maps.Keys(theme.Types)); !slices.Equal(actual, types) {
+			t.Errorf("%s: types do not match. Actual: %v - vs - Expected: %v", name, actual, types)
+		}
+		if len(theme.Bars) == 0 {
+			t.Errorf("%s: expected styles for stat bars", name)
+		}
+		for typ, style := range theme.Types {
+			if style.FG == nil || style.BG == nil {
+				t.Errorf("%s: expected %s to set both colors", name, typ)
+			}
+		}
+	}
+
+	if _, err := Load("neon"); err == nil {
+		t.Errorf("expected an error loading an unknown theme")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	fg, bg := Color{R: 255, G: 255, B: 255}, Color{R: 248, G: 208, B: 48}
+	style := Style{FG: &fg, BG: &bg}
+
+	cases := []struct {
+		depth    terminal.Depth
+		expected string
+	}{
+		{depth: terminal.TrueColor, expected: "\x1b[48;2;248;208;48m\x1b[38;2;255;255;255m Electric \x1b[0m"},
+		{depth: terminal.Color256, expected: "\x1b[48;5;221m\x1b[38;5;231m Electric \x1b[0m"},
+		{depth: terminal.Color16, expected: "\x1b[43m\x1b[97m Electric \x1b[0m"},
+		{depth: terminal.NoColor, expected: " Electric "},
+	}
+	for _, c := range cases {
+		if actual := style.Paint(c.depth, " Electric "); actual != c.expected {
+			t.Errorf("depth %d: Actual: %q - vs - Expected: %q", c.depth, actual, c.expected)
+		}
+	}
+
+	if actual := (Style{Bold: true}).Paint(terminal.TrueColor, "Name:"); actual != "\x1b[1mName:\x1b[0m" {
+		t.Errorf("bold does not match. Actual: %q - vs - Expected: %q", actual, "\x1b[1mName:\x1b[0m")
+	}
+
+	var c Color
+	if err := c.UnmarshalText([]byte("red")); err == nil {
+		t.Errorf("expected an error for a color that isn't #rrggbb")
+	}
+}
+
+func TestBadge(t *testing.T) {
+	theme, _ := Load(DefaultName)
+	if actual := theme.Badge(terminal.NoColor, "electric"); actual != "Electric" {
+		t.Errorf("badge does not match. Actual: %q - vs - Expected: %q", actual, "Electric")
+	}
+	expected := "\x1b[48;2;248;208;48m\x1b[38;2;255;255;255m Electric \x1b[0m"
+	if actual := theme.Badge(terminal.TrueColor, "electric"); actual != expected {
+		t.Errorf("badge does not match. Actual: %q - vs - Expected: %q", actual, expected)
+	}
+}
+
+func TestStatStyle(t *testing.T) {
+	theme, _ := Load(DefaultName)
+	cases := []struct {
+		value    int
+		expected int
+	}{
+		{value: 5, expected: 0},
+		{value: 45, expected: 1},
+		{value: 90, expected: 3},
+		{value: 150, expected: 4},
+		{value: 255, expected: 4},
+	}
+	for _, c := range cases {
+		if actual := theme.StatStyle(c.value); actual.FG == nil || *actual.FG != *theme.Bars[c.expected].FG {
+			t.Errorf("style of %d does not match bar %d", c.value, c.expected)
+		}
+	}
+}
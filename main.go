@@ -1,14 +1,68 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/fotis-sofoulis/pokedex-cli/internal/paths"
 	"github.com/fotis-sofoulis/pokedex-cli/internal/pokeapi"
 	"github.com/fotis-sofoulis/pokedex-cli/internal/pokecache"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/profile"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/terminal"
 )
 
 func main() {
+	dataDir := flag.String("data-dir", "", "directory for your caught Pokemon (env "+paths.DataDirEnv+")")
+	cacheDir := flag.String("cache-dir", "", "directory for sprites and downloaded responses (env "+paths.CacheDirEnv+")")
+	profileName := flag.String("profile", "", "trainer profile to play as (defaults to the last one used)")
+	colorMode := flag.String("color", "", "style output: auto, always or never (defaults to the color setting, then auto)")
+	flag.Parse()
+
+	if *colorMode != "" && !slices.Contains(terminal.ColorModes, *colorMode) {
+		fmt.Fprintf(os.Stderr, "--color must be one of %s\n", strings.Join(terminal.ColorModes, ", "))
+		os.Exit(2)
+	}
+
+	dirs, err := paths.Resolve(*dataDir, *cacheDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	moved, err := paths.MigrateLegacy(paths.LegacyDir, dirs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if moved > 0 {
+		fmt.Printf("Moved %d files from ./%s to %s and %s\n", moved, paths.LegacyDir, dirs.Data, dirs.Cache)
+	}
+
+	pokedex.SetCacheDir(dirs.Cache)
+
+	profiles := profile.NewManager(dirs.Data)
+	if moved, err := profiles.MigrateSave(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if moved {
+		fmt.Printf("Moved your Pokedex into the %s profile\n", profile.DefaultName)
+	}
+
+	name := *profileName
+	if name == "" {
+		name = profiles.Current()
+	}
+	trainer, err := profiles.Open(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	cache := pokecache.NewCache(5 * time.Second)
 	pokeapi.InitCache(cache)
-	startRepl()
+	pokeapi.SetCacheDir(filepath.Join(dirs.Cache, "http"))
+	startRepl(profiles, trainer, *colorMode)
 }
@@ -4,15 +4,38 @@ import (
 	"bufio"
 	"fmt"
 	"github.com/fotis-sofoulis/pokedex-cli/commands"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/pokedex"
+	"github.com/fotis-sofoulis/pokedex-cli/internal/profile"
 	"os"
 	"strings"
 )
 
-func startRepl() {
+func startRepl(profiles *profile.Manager, trainer profile.Profile, colorMode string) {
 	scanner := bufio.NewScanner(os.Stdin)
+
+	store := trainer.Store()
+	if from, err := store.Upgrade(); err != nil {
+		fmt.Println(err)
+	} else if from != pokedex.CurrentVersion {
+		fmt.Printf("Upgraded your Pokedex save from version %d to %d\n", from, pokedex.CurrentVersion)
+	}
+
 	cfg := &commands.Config{
-		Next:     nil,
-		Previous: nil,
+		Next:      nil,
+		Previous:  nil,
+		Profiles:  profiles,
+		ColorMode: colorMode,
+		Confirm: func(question string) bool {
+			fmt.Printf("%s [y/N] ", question)
+			if !scanner.Scan() {
+				return false
+			}
+			answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			return answer == "y" || answer == "yes"
+		},
+	}
+	if err := cfg.UseProfile(trainer); err != nil {
+		fmt.Println(err)
 	}
 	for {
 		fmt.Print("Pokedex > ")
@@ -32,6 +55,9 @@ func startRepl() {
 
 		cmd, exists := commands.GetCommands()[cmdName]
 		if exists {
+			if cmd.RawArgs {
+				args = strings.Fields(input)[1:]
+			}
 			err := cmd.Callback(cfg, args...)
 			if err != nil {
 				fmt.Println(err)